@@ -0,0 +1,50 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Services annotated with omni-kube-service-exposer.sidero.dev/port
+#
+data "omni_exposed_service" "grafana" {
+  cluster = "production"
+  label   = "Grafana"
+}
+
+output "grafana_url" {
+  value = data.omni_exposed_service.grafana.urls["grafana.monitoring"]
+}
+
+output "exposed_services" {
+  value = {
+    for s in data.omni_exposed_service.grafana.services : s.name => {
+      url  = s.url
+      port = s.port
+    }
+  }
+}
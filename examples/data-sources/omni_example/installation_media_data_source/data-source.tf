@@ -0,0 +1,62 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+resource "omni_schematic" "storage" {
+  talos_version = "v1.10.0"
+  extensions    = ["siderolabs/iscsi-tools"]
+}
+
+#
+# ISO joining Omni, with the extensions of the storage schematic
+#
+data "omni_installation_media" "storage_iso" {
+  talos_version = "v1.10.0"
+  platform      = "iso"
+  architecture  = "amd64"
+  schematic_id  = omni_schematic.storage.id
+}
+
+#
+# Raw image for the arm64 machines, the schematic is created by the data source
+#
+data "omni_installation_media" "metal_arm64" {
+  talos_version = "v1.10.0"
+  platform      = "metal"
+  architecture  = "arm64"
+  kernel_args   = ["console=ttyS0"]
+}
+
+output "storage_iso_url" {
+  value = data.omni_installation_media.storage_iso.url
+}
+
+output "metal_arm64_pxe_url" {
+  value = data.omni_installation_media.metal_arm64.pxe_url
+}
@@ -0,0 +1,70 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Workers attached one by one, each with its own hostname and address
+#
+variable "workers" {
+  type = map(object({
+    uuid    = string
+    address = string
+  }))
+}
+
+resource "omni_machine_set" "workers" {
+  cluster  = "omni-cluster-1"
+  name     = "workers"
+  role     = "worker"
+  machines = []
+}
+
+resource "omni_cluster_machine" "worker" {
+  for_each = var.workers
+
+  machine     = each.value.uuid
+  machine_set = omni_machine_set.workers.id
+
+  patches = [
+    {
+      name = "network"
+      inline = yamlencode({
+        machine = {
+          network = {
+            hostname = each.key
+            interfaces = [{
+              deviceSelector = { physical = true }
+              addresses      = [each.value.address]
+            }]
+          }
+        }
+      })
+    },
+  ]
+}
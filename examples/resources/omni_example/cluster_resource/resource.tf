@@ -28,6 +28,14 @@ provider "omni" {
   service_account = var.service_account
 }
 
+#
+# Set TF_VAR_registry_password
+#
+variable "registry_password" {
+  type      = string
+  sensitive = true
+}
+
 #
 # Change uuids of machines to make it works
 #
@@ -141,4 +149,34 @@ kind: Workers
 machines:
   - "954d3242-0d82-0cb3-f8de-7d3342c2051d"
 EOT
-}
\ No newline at end of file
+
+  sensitive_patches = [
+    {
+      kind = "Cluster"
+      name = "registry-auth"
+      inline = yamlencode({
+        machine = {
+          registries = {
+            config = {
+              "registry.mydomain" = {
+                auth = {
+                  username = "omni"
+                  password = var.registry_password
+                }
+              }
+            }
+          }
+        }
+      })
+    },
+  ]
+
+  wait_for_machines         = true
+  wait_for_machines_timeout = "10m" // default, the apply fails if the machines are not registered, connected and free by then
+
+  manifest_sync = {
+    mode    = "on_kubernetes_version_change"
+    dry_run = true
+    timeout = "15m"
+  }
+}
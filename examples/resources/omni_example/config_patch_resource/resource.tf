@@ -0,0 +1,69 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# NIC configuration owned by the network team, apart from the omni_cluster template
+#
+resource "omni_config_patch" "nic" {
+  name    = "network-interfaces"
+  weight  = 300
+  cluster = "omni-cluster-1"
+
+  patch = yamlencode({
+    machine = {
+      network = {
+        interfaces = [
+          {
+            interface = "eth0"
+            dhcp      = true
+            mtu       = 9000
+          },
+        ]
+      }
+    }
+  })
+}
+
+#
+# Patch of a single machine
+#
+resource "omni_config_patch" "hostname" {
+  name            = "hostname"
+  cluster         = "omni-cluster-1"
+  cluster_machine = "f3a1fa10-6f6b-4f36-9b0a-0b5c3a8d2b11"
+
+  patch = yamlencode({
+    machine = {
+      network = {
+        hostname = "worker-1"
+      }
+    }
+  })
+}
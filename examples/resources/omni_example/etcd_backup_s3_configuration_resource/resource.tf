@@ -0,0 +1,48 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Set TF_VAR_s3_secret_access_key
+#
+variable "s3_secret_access_key" {
+  type      = string
+  sensitive = true
+}
+
+#
+# Store of the etcd backups enabled with etcd_backup in omni_cluster
+#
+resource "omni_etcd_backup_s3_configuration" "minio" {
+  bucket            = "omni-etcd-backups"
+  region            = "us-east-1"
+  endpoint          = "https://minio.example.com:9000"
+  access_key_id     = "omni"
+  secret_access_key = var.s3_secret_access_key
+}
@@ -0,0 +1,48 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+variable "talos_version" {
+  type    = string
+  default = "v1.10.0"
+}
+
+#
+# Snapshot of etcd taken before every Talos upgrade of the cluster
+#
+resource "omni_etcd_manual_backup" "pre_upgrade" {
+  cluster = "talos-default"
+  triggers = {
+    talos_version = var.talos_version
+  }
+}
+
+output "pre_upgrade_backup" {
+  value = "${omni_etcd_manual_backup.pre_upgrade.id} (${omni_etcd_manual_backup.pre_upgrade.size} bytes, ${omni_etcd_manual_backup.pre_upgrade.created_at})"
+}
@@ -0,0 +1,47 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Service account of a bare metal infra provider, run it with
+# OMNI_ENDPOINT=var.omni_uri OMNI_SERVICE_ACCOUNT_KEY=$(terraform output -raw bare_metal_key)
+#
+resource "omni_infra_provider" "bare_metal" {
+  provider_id = "bare-metal"
+  ttl         = "2160h"
+}
+
+output "bare_metal_key" {
+  value     = omni_infra_provider.bare_metal.key
+  sensitive = true
+}
+
+output "bare_metal_healthy" {
+  value = omni_infra_provider.bare_metal.healthy
+}
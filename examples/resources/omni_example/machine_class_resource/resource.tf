@@ -0,0 +1,43 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Free machines labelled with the gpu profile (see omni_machine_labels)
+#
+resource "omni_machine_class" "gpu" {
+  name = "gpu-workers"
+  match_labels = [
+    "profile = gpu, !omni.sidero.dev/cluster",
+  ]
+}
+
+output "gpu_machines" {
+  value = omni_machine_class.gpu.matching_machines
+}
@@ -0,0 +1,55 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Hardware profiles of the machines, matched by omni_machine_class selectors
+#
+variable "machine_profiles" {
+  type = map(string)
+  default = {
+    "00:00:00:00:00:01" = "gpu"
+    "00:00:00:00:00:02" = "storage"
+  }
+}
+
+data "omni_machine" "machine" {
+  for_each = var.machine_profiles
+
+  hardware_address = each.key
+}
+
+resource "omni_machine_labels" "machine" {
+  for_each = var.machine_profiles
+
+  machine = data.omni_machine.machine[each.key].uuid
+  labels = {
+    profile = each.value
+  }
+}
@@ -0,0 +1,50 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Workers provisioned by the KubeVirt infra provider
+#
+resource "omni_machine_request_set" "workers" {
+  name          = "workers"
+  provider_id   = "kubevirt"
+  talos_version = "v1.10.0"
+  extensions    = ["siderolabs/qemu-guest-agent"]
+  provider_data = <<-EOT
+    cores: 4
+    memory: 8192
+    disk_size: 40
+  EOT
+  machine_count = 3
+  timeout       = "20m"
+}
+
+output "worker_machines" {
+  value = omni_machine_request_set.workers.machines
+}
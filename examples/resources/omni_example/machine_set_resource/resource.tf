@@ -0,0 +1,76 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Worker pools managed apart from the omni_cluster template
+#
+variable "pools" {
+  type = map(number)
+  default = {
+    "pool-a" = 2
+    "pool-b" = 3
+  }
+}
+
+resource "omni_machine_set" "pool" {
+  for_each = var.pools
+
+  cluster = "omni-cluster-1"
+  name    = each.key
+  role    = "worker"
+
+  machine_class = {
+    name  = "workers"
+    count = each.value
+  }
+
+  update_strategy = {
+    type            = "rolling"
+    max_parallelism = 1
+  }
+
+  delete_strategy = {
+    type            = "rolling"
+    max_parallelism = 1
+  }
+
+  patches = [
+    {
+      name = "pool-label"
+      inline = yamlencode({
+        machine = {
+          nodeLabels = {
+            pool = each.key
+          }
+        }
+      })
+    },
+  ]
+}
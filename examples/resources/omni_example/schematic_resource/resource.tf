@@ -0,0 +1,53 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Storage nodes need iSCSI and predictable interface names
+#
+resource "omni_schematic" "storage" {
+  talos_version = "v1.10.0"
+  extensions = [
+    "siderolabs/iscsi-tools",
+    "siderolabs/util-linux-tools",
+  ]
+  kernel_args = ["net.ifnames=0"]
+  meta_values = {
+    # Machine labels set on registration
+    "0x0c" = "machineLabels: {profile: storage}"
+  }
+}
+
+output "storage_pxe_url" {
+  value = omni_schematic.storage.pxe_url
+}
+
+output "storage_installer_image" {
+  value = omni_schematic.storage.installer_image
+}
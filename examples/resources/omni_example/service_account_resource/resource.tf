@@ -0,0 +1,48 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Key used by the CI pipelines, renewed by the first apply in the last 30 days of its lifetime
+#
+resource "omni_service_account" "ci" {
+  name          = "ci"
+  role          = "Operator"
+  ttl           = "2160h"
+  rotate_before = "720h"
+}
+
+output "ci_service_account_key" {
+  value     = omni_service_account.ci.key
+  sensitive = true
+}
+
+output "ci_service_account_expiration" {
+  value = omni_service_account.ci.expiration
+}
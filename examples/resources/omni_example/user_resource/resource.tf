@@ -0,0 +1,51 @@
+# Copyright (c) HashiCorp, Inc.
+
+terraform {
+  required_providers {
+    omni = {
+      version = "0.1.0"
+      source  = "flpajany/omni"
+    }
+  }
+}
+
+#
+# Set TF_VAR_service_account
+#
+variable "service_account" {
+  type = string
+}
+
+#
+# Set TF_VAR_omni_uri
+#
+variable "omni_uri" {
+  type = string
+}
+
+provider "omni" {
+  uri             = var.omni_uri
+  service_account = var.service_account
+}
+
+#
+# Platform team members, existing users are imported with
+# terraform import 'omni_user.platform["jane@example.com"]' jane@example.com
+#
+variable "platform_team" {
+  type = map(string)
+  default = {
+    "jane@example.com" = "Admin"
+    "john@example.com" = "Operator"
+  }
+}
+
+resource "omni_user" "platform" {
+  for_each = var.platform_team
+
+  email = each.key
+  role  = each.value
+  saml_labels = {
+    groups = "platform"
+  }
+}
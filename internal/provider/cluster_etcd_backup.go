@@ -0,0 +1,275 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"gopkg.in/yaml.v3"
+)
+
+// EtcdBackupModel describes the etcd_backup attribute of omni_cluster.
+type EtcdBackupModel struct {
+	Enabled  types.Bool   `tfsdk:"enabled"`
+	Interval types.String `tfsdk:"interval"`
+}
+
+var etcdBackupAttrTypes = map[string]attr.Type{
+	"enabled":  types.BoolType,
+	"interval": types.StringType,
+}
+
+// RestoreFromModel describes the restore_from attribute of omni_cluster.
+type RestoreFromModel struct {
+	ClusterUUID types.String `tfsdk:"cluster_uuid"`
+	BackupID    types.String `tfsdk:"backup_id"`
+}
+
+var restoreFromAttrTypes = map[string]attr.Type{
+	"cluster_uuid": types.StringType,
+	"backup_id":    types.StringType,
+}
+
+func restoreFromSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Seed the control plane from an etcd backup of another cluster. Only honored when the cluster is created, it can be removed afterwards but not changed",
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"cluster_uuid": schema.StringAttribute{
+				MarkdownDescription: "UUID of the cluster the backup was taken from",
+				Required:            true,
+			},
+			"backup_id": schema.StringAttribute{
+				MarkdownDescription: "Snapshot name of the etcd backup to restore",
+				Required:            true,
+			},
+		},
+	}
+}
+
+// restoreFromFrom reads the restore_from attribute, nil when not set.
+func restoreFromFrom(ctx context.Context, data OmniClusterResourceModel) (*RestoreFromModel, diag.Diagnostics) {
+	if data.RestoreFrom.IsNull() || data.RestoreFrom.IsUnknown() {
+		return nil, nil
+	}
+
+	var m RestoreFromModel
+	diags := data.RestoreFrom.As(ctx, &m, basetypes.ObjectAsOptions{})
+
+	return &m, diags
+}
+
+// renderRestoreFrom sets the bootstrapSpec of the ControlPlane document.
+func renderRestoreFrom(template string, m *RestoreFromModel) (string, error) {
+	if m == nil {
+		return template, nil
+	}
+
+	docs, err := templateDocuments(template)
+	if err != nil {
+		return "", err
+	}
+
+	for _, doc := range docs {
+		if !isPatchTarget(doc, "ControlPlane", "") {
+			continue
+		}
+
+		c := doc.Content[0]
+		removeMappingKey(c, "bootstrapSpec")
+		c.Content = append(c.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "bootstrapSpec"},
+			&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: "clusterUUID"},
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.ClusterUUID.ValueString()},
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: "snapshot"},
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.BackupID.ValueString()},
+			}},
+		)
+
+		return encodeTemplateDocuments(docs)
+	}
+
+	return "", fmt.Errorf("no ControlPlane document in template")
+}
+
+func etcdBackupSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Etcd backup settings, rendered into the Cluster document of the template. When not set, it reflects the backup configuration of the cluster",
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.UseStateForUnknown(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Enable periodic etcd backups (requires the S3 backup store to be configured in Omni)",
+				Required:            true,
+			},
+			"interval": schema.StringAttribute{
+				MarkdownDescription: "Interval between backups (e.g. `1h`), required when enabled",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// etcdBackupFrom reads the etcd_backup attribute, nil when not set.
+func etcdBackupFrom(ctx context.Context, data OmniClusterResourceModel) (*EtcdBackupModel, diag.Diagnostics) {
+	if data.EtcdBackup.IsNull() || data.EtcdBackup.IsUnknown() {
+		return nil, nil
+	}
+
+	var m EtcdBackupModel
+	diags := data.EtcdBackup.As(ctx, &m, basetypes.ObjectAsOptions{})
+
+	return &m, diags
+}
+
+// validateEtcdBackup checks the etcd_backup attribute without needing Omni.
+func validateEtcdBackup(m *EtcdBackupModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if m == nil || m.Enabled.IsUnknown() || m.Interval.IsUnknown() {
+		return diags
+	}
+
+	if m.Enabled.ValueBool() && m.Interval.IsNull() {
+		diags.AddAttributeError(path.Root("etcd_backup").AtName("interval"), "Missing Attribute Configuration", "interval must be set when etcd backups are enabled.")
+		return diags
+	}
+
+	if !m.Interval.IsNull() {
+		if interval, err := time.ParseDuration(m.Interval.ValueString()); err != nil || interval <= 0 {
+			diags.AddAttributeError(path.Root("etcd_backup").AtName("interval"), "Invalid Attribute Value", "interval must be a positive duration like 1h.")
+		}
+	}
+
+	return diags
+}
+
+// renderEtcdBackup sets features.backupConfiguration of the Cluster document.
+func renderEtcdBackup(template string, m *EtcdBackupModel) (string, error) {
+	if m == nil {
+		return template, nil
+	}
+
+	docs, err := templateDocuments(template)
+	if err != nil {
+		return "", err
+	}
+
+	for _, doc := range docs {
+		if !isPatchTarget(doc, "Cluster", "") {
+			continue
+		}
+
+		c := doc.Content[0]
+		features := mappingValue(c, "features")
+		if features == nil {
+			features = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			c.Content = append(c.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "features"}, features)
+		}
+
+		removeMappingKey(features, "backupConfiguration")
+
+		if m.Enabled.ValueBool() {
+			features.Content = append(features.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "backupConfiguration"},
+				&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Tag: "!!str", Value: "interval"},
+					{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.Interval.ValueString()},
+				}},
+			)
+		}
+
+		if len(features.Content) == 0 {
+			removeMappingKey(c, "features")
+		}
+
+		return encodeTemplateDocuments(docs)
+	}
+
+	return "", fmt.Errorf("no Cluster document in template")
+}
+
+// readEtcdBackup refreshes the etcd_backup attribute out of the live cluster spec, whenever the cluster
+// has a backup configuration or etcd_backup is set. The configured interval string is kept when it
+// matches the live interval.
+func (r *OmniClusterResource) readEtcdBackup(ctx context.Context, name string, data *OmniClusterResourceModel) diag.Diagnostics {
+	current, diags := etcdBackupFrom(ctx, *data)
+	if diags.HasError() {
+		return diags
+	}
+
+	cluster, err := r.client.GetCluster(name)
+	if err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to get cluster, got error: %s", err))
+		return diags
+	}
+
+	conf := cluster.TypedSpec().Value.GetBackupConfiguration()
+	if conf == nil && current == nil {
+		data.EtcdBackup = types.ObjectNull(etcdBackupAttrTypes)
+		return diags
+	}
+	if current == nil {
+		current = &EtcdBackupModel{Enabled: types.BoolNull(), Interval: types.StringNull()}
+	}
+
+	live := EtcdBackupModel{
+		Enabled:  types.BoolValue(conf.GetEnabled()),
+		Interval: types.StringNull(),
+	}
+
+	if conf.GetInterval() != nil {
+		interval := conf.GetInterval().AsDuration()
+		live.Interval = types.StringValue(interval.String())
+
+		if configured, err := time.ParseDuration(current.Interval.ValueString()); err == nil && configured == interval {
+			live.Interval = current.Interval
+		}
+	}
+
+	if !live.Enabled.ValueBool() && current.Interval.IsNull() {
+		live.Interval = types.StringNull()
+	}
+
+	obj, d := types.ObjectValueFrom(ctx, etcdBackupAttrTypes, live)
+	diags.Append(d...)
+	data.EtcdBackup = obj
+
+	return diags
+}
+
+// checkEtcdBackupStore makes sure the S3 backup store is configured in Omni.
+func (r *OmniClusterResource) checkEtcdBackupStore() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	status, err := r.client.GetEtcdBackupStoreStatus()
+	if err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to get etcd backup store status, got error: %s", err))
+		return diags
+	}
+
+	spec := status.TypedSpec().Value
+	switch {
+	case spec.ConfigurationError != "":
+		diags.AddAttributeError(path.Root("etcd_backup"), "Etcd Backup Store Misconfigured", fmt.Sprintf("the etcd backup store of Omni reports: %s", spec.ConfigurationError))
+	case spec.ConfigurationName != "s3":
+		diags.AddAttributeError(path.Root("etcd_backup"), "Etcd Backup Store Not Configured", fmt.Sprintf("etcd backups need the S3 backup store, Omni uses %q", spec.ConfigurationName))
+	}
+
+	return diags
+}
@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRenderEtcdBackup(t *testing.T) {
+	enabled := &EtcdBackupModel{Enabled: types.BoolValue(true), Interval: types.StringValue("2h")}
+
+	rendered, err := renderEtcdBackup(testClusterTemplate, enabled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(rendered, "features:\n  backupConfiguration:\n    interval: 2h\n") {
+		t.Errorf("expected backupConfiguration to be rendered, got:\n%s", rendered)
+	}
+
+	disabled := &EtcdBackupModel{Enabled: types.BoolValue(false), Interval: types.StringNull()}
+
+	rendered, err = renderEtcdBackup(rendered, disabled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(rendered, "backupConfiguration") || strings.Contains(rendered, "features") {
+		t.Errorf("expected backupConfiguration to be removed, got:\n%s", rendered)
+	}
+
+	if diags := validateEtcdBackup(&EtcdBackupModel{Enabled: types.BoolValue(true), Interval: types.StringNull()}); !diags.HasError() {
+		t.Error("expected an error when interval is missing")
+	}
+	if diags := validateEtcdBackup(enabled); diags.HasError() {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
+
+func TestRenderRestoreFrom(t *testing.T) {
+	rendered, err := renderRestoreFrom(testClusterTemplate, &RestoreFromModel{
+		ClusterUUID: types.StringValue("0b8c6a2e-2f1c-4d43-9c2e-5e1c1f3a7b90"),
+		BackupID:    types.StringValue("1714400000.snapshot"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "kind: ControlPlane\nmachines:\n  - \"d7413242-47ce-2140-0eee-cefb3e72d13e\"\nbootstrapSpec:\n  clusterUUID: 0b8c6a2e-2f1c-4d43-9c2e-5e1c1f3a7b90\n  snapshot: 1714400000.snapshot\n"
+	if !strings.Contains(rendered, expected) {
+		t.Errorf("expected bootstrapSpec to be rendered, got:\n%s", rendered)
+	}
+}
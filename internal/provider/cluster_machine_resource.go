@@ -0,0 +1,356 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+const defaultClusterMachineTimeout = 30 * time.Minute
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniClusterMachineResource{}
+var _ resource.ResourceWithImportState = &OmniClusterMachineResource{}
+var _ resource.ResourceWithValidateConfig = &OmniClusterMachineResource{}
+
+func NewOmniClusterMachineResource() resource.Resource {
+	return &OmniClusterMachineResource{}
+}
+
+// OmniClusterMachineResource defines the resource implementation.
+type OmniClusterMachineResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniClusterMachineResourceModel describes the resource data model.
+type OmniClusterMachineResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Machine      types.String `tfsdk:"machine"`
+	MachineSet   types.String `tfsdk:"machine_set"`
+	Cluster      types.String `tfsdk:"cluster"`
+	Patches      types.List   `tfsdk:"patches"`
+	ReadyTimeout types.String `tfsdk:"ready_timeout"`
+
+	Stage types.String `tfsdk:"stage"`
+	Ready types.Bool   `tfsdk:"ready"`
+}
+
+func (r *OmniClusterMachineResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_machine"
+}
+
+func (r *OmniClusterMachineResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_cluster_machine resource. Attaches a single machine to a machine set with its own config patches. " +
+			"The machine set must list its machines explicitly, not allocate them from a machine class, and the machine must not be attached already by a cluster template or another resource",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Machine UUID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"machine": schema.StringAttribute{
+				MarkdownDescription: "UUID of the machine",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"machine_set": schema.StringAttribute{
+				MarkdownDescription: "ID of the machine set, e.g. `omni_machine_set.workers.id` or `<cluster>-workers`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "Name of the cluster of the machine set",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"patches": schema.ListNestedAttribute{
+				MarkdownDescription: "Config patches applied to this machine only, e.g. its hostname and addresses. Names must be unique, patches are applied in name order",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Patch name",
+							Required:            true,
+						},
+						"inline": schema.StringAttribute{
+							MarkdownDescription: "Patch content in YAML",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"ready_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration to wait for the machine to be ready on create and update, " +
+					"and to be removed on delete (default `30m`)",
+				Optional: true,
+			},
+			"stage": schema.StringAttribute{
+				MarkdownDescription: "Machine stage reported by Omni (INSTALLING, RUNNING, ...)",
+				Computed:            true,
+			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the machine is ready",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *OmniClusterMachineResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniClusterMachineResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniClusterMachineResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.Machine
+
+	applied, diags := r.apply(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if !applied {
+		return
+	}
+
+	tflog.Trace(ctx, "create a resource omni_cluster_machine")
+
+	// Save data into Terraform state, even on a readiness failure as the machine is attached
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniClusterMachineResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniClusterMachineResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cm, err := r.client.GetClusterMachine(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read cluster machine, got error: %s", err))
+		return
+	}
+
+	data.Machine = types.StringValue(cm.Machine)
+	data.MachineSet = types.StringValue(cm.MachineSet)
+	data.Cluster = types.StringValue(cm.Cluster)
+
+	var diags diag.Diagnostics
+	data.Patches, diags = patchesToModel(ctx, data.Patches, cm.Patches)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.readClusterMachineStatus(&data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster machine status, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniClusterMachineResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniClusterMachineResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied, diags := r.apply(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if !applied {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniClusterMachineResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniClusterMachineResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := clusterMachineTimeout(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteClusterMachine(data.ID.ValueString(), timeout); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete cluster machine, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniClusterMachineResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniClusterMachineResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniClusterMachineResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	patches, diags := patchesFrom(ctx, data.Patches)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(validatePatchNames(patches)...)
+	for i, patch := range patches {
+		if patch.Inline.IsUnknown() {
+			continue
+		}
+
+		if err := validatePatch(patch.Inline.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("patches").AtListIndex(i).AtName("inline"), "Invalid Attribute Value",
+				fmt.Sprintf("patch %q: %s", patch.Name.ValueString(), err))
+		}
+	}
+
+	if !data.ReadyTimeout.IsNull() && !data.ReadyTimeout.IsUnknown() {
+		if _, err := time.ParseDuration(data.ReadyTimeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ready_timeout"), "Invalid Attribute Value",
+				fmt.Sprintf("ready_timeout must be a duration like 30m: %s", err))
+		}
+	}
+}
+
+// apply attaches the machine to its machine set and waits for it to be ready. It tells whether
+// the machine got attached, in which case the state must be saved even along errors.
+func (r *OmniClusterMachineResource) apply(ctx context.Context, data *OmniClusterMachineResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	timeout, d := clusterMachineTimeout(*data)
+	diags.Append(d...)
+
+	patches, d := patchesFrom(ctx, data.Patches)
+	diags.Append(d...)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	cm := omniapi.ClusterMachine{
+		Machine:    data.ID.ValueString(),
+		MachineSet: data.MachineSet.ValueString(),
+	}
+	for _, patch := range patches {
+		cm.Patches = append(cm.Patches, omniapi.Patch{Name: patch.Name.ValueString(), Data: patch.Inline.ValueString()})
+	}
+
+	data.Stage = types.StringNull()
+	data.Ready = types.BoolValue(false)
+
+	applied, err := r.client.ApplyClusterMachine(cm)
+	if err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to apply cluster machine, got error: %s", err))
+		return false, diags
+	}
+
+	data.Cluster = types.StringValue(applied.Cluster)
+
+	if _, err := r.client.WaitForClusterMachineReady(cm.Machine, timeout); err != nil {
+		diags.AddError("client Error", fmt.Sprintf("cluster machine is not ready, got error: %s", err))
+	}
+
+	if err := r.readClusterMachineStatus(data); err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to get cluster machine status, got error: %s", err))
+	}
+
+	return true, diags
+}
+
+func (r *OmniClusterMachineResource) readClusterMachineStatus(data *OmniClusterMachineResourceModel) error {
+	status, err := r.client.GetClusterMachineStatus(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		data.Stage = types.StringNull()
+		data.Ready = types.BoolValue(false)
+
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	spec := status.TypedSpec().Value
+	data.Stage = types.StringValue(spec.Stage.String())
+	data.Ready = types.BoolValue(spec.Ready)
+
+	return nil
+}
+
+func clusterMachineTimeout(data OmniClusterMachineResourceModel) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if data.ReadyTimeout.IsNull() {
+		return defaultClusterMachineTimeout, diags
+	}
+
+	timeout, err := time.ParseDuration(data.ReadyTimeout.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("ready_timeout"), "Invalid Attribute Value", err.Error())
+	}
+
+	return timeout, diags
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+func TestAccOmniClusterMachineResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccOmniClusterMachineResourceConfig("worker-1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_cluster_machine.test", "cluster", "test-cluster-1"),
+					resource.TestCheckResourceAttr("omni_cluster_machine.test", "stage", "RUNNING"),
+					resource.TestCheckResourceAttr("omni_cluster_machine.test", "ready", "true"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "omni_cluster_machine.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ready_timeout"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccOmniClusterMachineResourceConfig("worker-2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_cluster_machine.test", "ready", "true"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniClusterMachineResourceConfig(hostname string) string {
+	return fmt.Sprintf(`
+resource "omni_machine_set" "test" {
+  cluster  = "test-cluster-1"
+  name     = "single"
+  role     = "worker"
+  machines = []
+}
+
+resource "omni_cluster_machine" "test" {
+  machine     = "00000000-0000-0000-0000-000000000001"
+  machine_set = omni_machine_set.test.id
+
+  patches = [
+    {
+      name   = "hostname"
+      inline = <<-EOT
+        machine:
+          network:
+            hostname: %s
+      EOT
+    },
+  ]
+}
+`, hostname)
+}
+
+func TestClusterMachinePatchesToModel(t *testing.T) {
+	ctx := context.Background()
+
+	configured := "machine:\n  network:\n    hostname: worker-1\n"
+	data := OmniClusterMachineResourceModel{
+		Patches: types.ListValueMust(types.ObjectType{AttrTypes: patchAttrTypes}, []attr.Value{
+			types.ObjectValueMust(patchAttrTypes, map[string]attr.Value{
+				"name":   types.StringValue("hostname"),
+				"inline": types.StringValue(configured),
+			}),
+		}),
+	}
+
+	var diags diag.Diagnostics
+	data.Patches, diags = patchesToModel(ctx, data.Patches, []omniapi.Patch{
+		{Name: "hostname", Data: "machine: {network: {hostname: worker-1}}"},
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	patches, _ := patchesFrom(ctx, data.Patches)
+	if len(patches) != 1 || patches[0].Inline.ValueString() != configured {
+		t.Errorf("equal patch should keep its configured formatting, got %v", patches)
+	}
+
+	data.Patches = types.ListNull(types.ObjectType{AttrTypes: patchAttrTypes})
+	if data.Patches, diags = patchesToModel(ctx, data.Patches, nil); diags.HasError() || !data.Patches.IsNull() {
+		t.Errorf("unset patches should stay null, got %v", data.Patches)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	manifestSyncModeNever                     = "never"
+	manifestSyncModeOnKubernetesVersionChange = "on_kubernetes_version_change"
+	manifestSyncModeAlways                    = "always"
+)
+
+// ManifestSyncModel describes the manifest_sync attribute of omni_cluster.
+type ManifestSyncModel struct {
+	Mode     types.String `tfsdk:"mode"`
+	DryRun   types.Bool   `tfsdk:"dry_run"`
+	OnCreate types.Bool   `tfsdk:"on_create"`
+	Timeout  types.String `tfsdk:"timeout"`
+}
+
+var manifestSyncAttrTypes = map[string]attr.Type{
+	"mode":      types.StringType,
+	"dry_run":   types.BoolType,
+	"on_create": types.BoolType,
+	"timeout":   types.StringType,
+}
+
+// ManifestSyncSummaryModel describes the last_manifest_sync attribute of omni_cluster.
+type ManifestSyncSummaryModel struct {
+	Time     types.String `tfsdk:"time"`
+	Changed  types.List   `tfsdk:"changed"`
+	Skipped  types.Int64  `tfsdk:"skipped"`
+	Rollouts types.List   `tfsdk:"rollouts"`
+}
+
+var manifestSyncSummaryAttrTypes = map[string]attr.Type{
+	"time":     types.StringType,
+	"changed":  types.ListType{ElemType: types.StringType},
+	"skipped":  types.Int64Type,
+	"rollouts": types.ListType{ElemType: types.StringType},
+}
+
+func manifestSyncSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Policy for syncing Kubernetes bootstrap manifests",
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "When to sync manifests on update: `never`, `on_kubernetes_version_change` or `always`",
+				Required:            true,
+			},
+			"dry_run": schema.BoolAttribute{
+				MarkdownDescription: "Show pending manifest diffs as warnings at plan time. The diffs are computed against the running cluster, so they are skipped when the Kubernetes version changes as the manifests of the new version are not known before the upgrade",
+				Optional:            true,
+			},
+			"on_create": schema.BoolAttribute{
+				MarkdownDescription: "Sync manifests once the cluster is created",
+				Optional:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration to wait for the rollouts following a sync (e.g. `10m`)",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func manifestSyncSummarySchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Summary of the last Kubernetes manifests sync done by the provider",
+		Computed:            true,
+		Attributes: map[string]schema.Attribute{
+			"time": schema.StringAttribute{
+				MarkdownDescription: "When the sync happened (RFC3339)",
+				Computed:            true,
+			},
+			"changed": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Manifests applied with changes",
+				Computed:            true,
+			},
+			"skipped": schema.Int64Attribute{
+				MarkdownDescription: "Number of manifests without changes",
+				Computed:            true,
+			},
+			"rollouts": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Workloads waited for rollout",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// manifestSyncSummary builds the last_manifest_sync value out of the sync results.
+func manifestSyncSummary(ctx context.Context, results []omniapi.ManifestSyncResult) (types.Object, diag.Diagnostics) {
+	changed := []string{}
+	rollouts := []string{}
+	var skipped int64
+
+	for _, res := range results {
+		switch {
+		case res.Rollout:
+			rollouts = append(rollouts, res.Path)
+		case res.Skipped:
+			skipped++
+		default:
+			changed = append(changed, res.Path)
+		}
+	}
+
+	return types.ObjectValueFrom(ctx, manifestSyncSummaryAttrTypes, ManifestSyncSummaryModel{
+		Time:     types.StringValue(time.Now().UTC().Format(time.RFC3339)),
+		Changed:  types.ListValueMust(types.StringType, stringValues(changed)),
+		Skipped:  types.Int64Value(skipped),
+		Rollouts: types.ListValueMust(types.StringType, stringValues(rollouts)),
+	})
+}
+
+// manifestSyncDiagnostics reports every changed manifest as a warning.
+// When the sync failed, an error listing what was processed before the failure is added too.
+func manifestSyncDiagnostics(results []omniapi.ManifestSyncResult, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var processed []string
+
+	for _, res := range results {
+		switch {
+		case res.Rollout:
+			processed = append(processed, fmt.Sprintf("rollout %s", res.Path))
+		case res.Skipped:
+			processed = append(processed, fmt.Sprintf("unchanged %s", res.Path))
+		default:
+			processed = append(processed, fmt.Sprintf("applied %s", res.Path))
+			diags.AddWarning(fmt.Sprintf("Kubernetes manifest %s updated", res.Path), res.Diff)
+		}
+	}
+
+	if err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to force sync manifests, got error: %s\n\nprocessed before failure:\n%s", err, strings.Join(processed, "\n")))
+	}
+
+	return diags
+}
+
+// manifestSyncPolicy is the resolved manifest sync configuration of a cluster.
+type manifestSyncPolicy struct {
+	mode     string
+	dryRun   bool
+	onCreate bool
+	timeout  time.Duration
+}
+
+// manifestSyncPolicyFrom resolves the policy out of manifest_sync, falling back to
+// the deprecated force_manifest_updating flag when the block is not set.
+func manifestSyncPolicyFrom(ctx context.Context, data OmniClusterResourceModel) (manifestSyncPolicy, diag.Diagnostics) {
+	policy := manifestSyncPolicy{mode: manifestSyncModeNever}
+
+	if data.ManifestSync.IsNull() || data.ManifestSync.IsUnknown() {
+		if data.ForceManifestUpdating.ValueBool() {
+			policy.mode = manifestSyncModeAlways
+		}
+		return policy, nil
+	}
+
+	var m ManifestSyncModel
+	diags := data.ManifestSync.As(ctx, &m, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return policy, diags
+	}
+
+	if !m.Mode.IsNull() && !m.Mode.IsUnknown() {
+		policy.mode = m.Mode.ValueString()
+	}
+	policy.dryRun = m.DryRun.ValueBool()
+	policy.onCreate = m.OnCreate.ValueBool()
+
+	if !m.Timeout.IsNull() && !m.Timeout.IsUnknown() {
+		timeout, err := time.ParseDuration(m.Timeout.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("manifest_sync").AtName("timeout"), "Invalid Attribute Value", fmt.Sprintf("timeout must be a duration like 10m: %s", err))
+			return policy, diags
+		}
+		policy.timeout = timeout
+	}
+
+	switch policy.mode {
+	case manifestSyncModeNever, manifestSyncModeOnKubernetesVersionChange, manifestSyncModeAlways:
+	default:
+		diags.AddAttributeError(path.Root("manifest_sync").AtName("mode"), "Invalid Attribute Value",
+			fmt.Sprintf("mode must be one of %s, %s or %s, got %q", manifestSyncModeNever, manifestSyncModeOnKubernetesVersionChange, manifestSyncModeAlways, policy.mode))
+	}
+
+	return policy, diags
+}
+
+// syncOnUpdate tells whether manifests must be synced when going from stateTemplate to planTemplate.
+func (p manifestSyncPolicy) syncOnUpdate(stateTemplate, planTemplate string) (bool, error) {
+	switch p.mode {
+	case manifestSyncModeAlways:
+		return true, nil
+	case manifestSyncModeOnKubernetesVersionChange:
+		stateVersion, err := clusterKubernetesVersion(stateTemplate)
+		if err != nil {
+			return false, err
+		}
+		planVersion, err := clusterKubernetesVersion(planTemplate)
+		if err != nil {
+			return false, err
+		}
+		return stateVersion != planVersion, nil
+	default:
+		return false, nil
+	}
+}
+
+// syncOnCreate tells whether manifests must be synced right after the cluster creation.
+func (p manifestSyncPolicy) syncOnCreate() bool {
+	return p.onCreate && p.mode != manifestSyncModeNever
+}
+
+func clusterKubernetesVersion(template string) (string, error) {
+	T := struct {
+		Kind       string
+		Kubernetes struct {
+			Version string
+		}
+	}{}
+
+	d := yaml.NewDecoder(strings.NewReader(template))
+	for {
+		err := d.Decode(&T)
+		if err != nil {
+			return "", fmt.Errorf("cluster kubernetes version not found : %v", err)
+		}
+		if T.Kind == "Cluster" {
+			return T.Kubernetes.Version, nil
+		}
+	}
+}
+
+func stringValues(l []string) []attr.Value {
+	values := make([]attr.Value, 0, len(l))
+	for _, v := range l {
+		values = append(values, types.StringValue(v))
+	}
+
+	return values
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+func TestManifestSyncSummary(t *testing.T) {
+	results := []omniapi.ManifestSyncResult{
+		{Path: "apps/v1/Deployment/kube-system/coredns", Diff: "- replicas: 1\n+ replicas: 2"},
+		{Path: "v1/ServiceAccount/kube-system/coredns", Skipped: true},
+		{Path: "apps/v1/Deployment/kube-system/coredns", Rollout: true},
+	}
+
+	obj, diags := manifestSyncSummary(context.Background(), results)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	var summary ManifestSyncSummaryModel
+	if diags := obj.As(context.Background(), &summary, basetypes.ObjectAsOptions{}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if summary.Skipped.ValueInt64() != 1 {
+		t.Errorf("expected 1 skipped manifest, got %d", summary.Skipped.ValueInt64())
+	}
+	if len(summary.Changed.Elements()) != 1 {
+		t.Errorf("expected 1 changed manifest, got %d", len(summary.Changed.Elements()))
+	}
+	if len(summary.Rollouts.Elements()) != 1 {
+		t.Errorf("expected 1 rollout, got %d", len(summary.Rollouts.Elements()))
+	}
+
+	diags = manifestSyncDiagnostics(results, nil)
+	if diags.WarningsCount() != 1 || diags.HasError() {
+		t.Errorf("expected a single warning, got %v", diags)
+	}
+
+	diags = manifestSyncDiagnostics(results, errors.New("rollout failed"))
+	if diags.ErrorsCount() != 1 {
+		t.Errorf("expected an error, got %v", diags)
+	}
+}
+
+func TestManifestSyncPolicySyncOnUpdate(t *testing.T) {
+	oldTemplate := `
+kind: Cluster
+name: test-cluster-1
+kubernetes:
+  version: "v1.29.9"
+talos:
+  version: "v1.6.8"`
+	newTemplate := `
+kind: Cluster
+name: test-cluster-1
+kubernetes:
+  version: "v1.30.4"
+talos:
+  version: "v1.6.8"`
+
+	for _, tc := range []struct {
+		mode     string
+		from, to string
+		expected bool
+	}{
+		{manifestSyncModeNever, oldTemplate, newTemplate, false},
+		{manifestSyncModeAlways, oldTemplate, oldTemplate, true},
+		{manifestSyncModeOnKubernetesVersionChange, oldTemplate, oldTemplate, false},
+		{manifestSyncModeOnKubernetesVersionChange, oldTemplate, newTemplate, true},
+	} {
+		sync, err := manifestSyncPolicy{mode: tc.mode}.syncOnUpdate(tc.from, tc.to)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.mode, err)
+		}
+		if sync != tc.expected {
+			t.Errorf("%s: expected %t, got %t", tc.mode, tc.expected, sync)
+		}
+	}
+}
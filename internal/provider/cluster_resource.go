@@ -7,19 +7,35 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/flpajany/terraform-provider-omni/omniapi"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
 	"gopkg.in/yaml.v3"
 )
 
+// defaultWaitForMachinesTimeout bounds the wait for the template machines when wait_for_machines_timeout is not set.
+const defaultWaitForMachinesTimeout = 10 * time.Minute
+
+// defaultClusterReadyTimeout bounds the wait for the control plane on create when ready_timeout is not set.
+const defaultClusterReadyTimeout = 30 * time.Minute
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OmniClusterResource{}
 var _ resource.ResourceWithImportState = &OmniClusterResource{}
+var _ resource.ResourceWithValidateConfig = &OmniClusterResource{}
+var _ resource.ResourceWithModifyPlan = &OmniClusterResource{}
 
 func NewOmniClusterResource() resource.Resource {
 	return &OmniClusterResource{}
@@ -32,11 +48,34 @@ type OmniClusterResource struct {
 
 // OmniClusterResourceModel describes the resource data model.
 type OmniClusterResourceModel struct {
-	Template              types.String `tfsdk:"template"`
-	TemplateComputed      types.String `tfsdk:"template_computed"`
-	ID                    types.String `tfsdk:"id"`
-	ForceManifestUpdating types.Bool   `tfsdk:"force_manifest_updating"`
-	DeleteMachineLinks    types.Bool   `tfsdk:"delete_machine_links"`
+	Template               types.String `tfsdk:"template"`
+	TemplateVars           types.Map    `tfsdk:"template_vars"`
+	TemplateComputed       types.String `tfsdk:"template_computed"`
+	ID                     types.String `tfsdk:"id"`
+	ForceManifestUpdating  types.Bool   `tfsdk:"force_manifest_updating"`
+	DeleteMachineLinks     types.Bool   `tfsdk:"delete_machine_links"`
+	ManifestSync           types.Object `tfsdk:"manifest_sync"`
+	WaitForMachines        types.Bool   `tfsdk:"wait_for_machines"`
+	WaitForMachinesTimeout types.String `tfsdk:"wait_for_machines_timeout"`
+	ReadyTimeout           types.String `tfsdk:"ready_timeout"`
+	SensitivePatches       types.List   `tfsdk:"sensitive_patches"`
+	EtcdBackup             types.Object `tfsdk:"etcd_backup"`
+	RestoreFrom            types.Object `tfsdk:"restore_from"`
+
+	Phase                       types.String `tfsdk:"phase"`
+	Ready                       types.Bool   `tfsdk:"ready"`
+	MachinesTotal               types.Int64  `tfsdk:"machines_total"`
+	MachinesHealthy             types.Int64  `tfsdk:"machines_healthy"`
+	ControlPlaneMachinesTotal   types.Int64  `tfsdk:"controlplane_machines_total"`
+	ControlPlaneMachinesHealthy types.Int64  `tfsdk:"controlplane_machines_healthy"`
+	WorkerMachinesTotal         types.Int64  `tfsdk:"worker_machines_total"`
+	WorkerMachinesHealthy       types.Int64  `tfsdk:"worker_machines_healthy"`
+	TalosVersion                types.String `tfsdk:"talos_version"`
+	KubernetesVersion           types.String `tfsdk:"kubernetes_version"`
+	KubernetesAPIURL            types.String `tfsdk:"kubernetes_api_url"`
+	ClusterUUID                 types.String `tfsdk:"cluster_uuid"`
+
+	LastManifestSync types.Object `tfsdk:"last_manifest_sync"`
 }
 
 func (r *OmniClusterResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -45,14 +84,23 @@ func (r *OmniClusterResource) Metadata(ctx context.Context, req resource.Metadat
 
 func (r *OmniClusterResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: omniClusterSchemaVersion,
+
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "omni_cluster resource",
+		MarkdownDescription: "omni_cluster resource. Machine sets, config patches and machine set nodes created by the standalone resources " +
+			"of the provider (`omni_machine_set`, `omni_config_patch`, `omni_cluster_machine`) carry the `terraform-provider-omni/managed-by` label: " +
+			"they are left out of the template sync, so a sync does not destroy them, and out of `template_computed`",
 
 		Attributes: map[string]schema.Attribute{
 			"template": schema.StringAttribute{
 				MarkdownDescription: "Template in YAML for managing Omni Cluster",
 				Required:            true,
 			},
+			"template_vars": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Variables substituted into `template` where `{{ name }}` placeholders appear. Templates are rendered only when this is set",
+				Optional:            true,
+			},
 			"template_computed": schema.StringAttribute{
 				MarkdownDescription: "Template in YAML formatted by Omni",
 				Computed:            true,
@@ -64,11 +112,113 @@ func (r *OmniClusterResource) Schema(ctx context.Context, req resource.SchemaReq
 			"force_manifest_updating": schema.BoolAttribute{
 				MarkdownDescription: "When updating a template, apply automatically updates to manifests",
 				Optional:            true,
+				DeprecationMessage:  "Use manifest_sync with mode = \"always\" instead",
 			},
+			"wait_for_machines": schema.BoolAttribute{
+				MarkdownDescription: "Wait for machines referenced by the template to be registered, connected and free instead of failing right away",
+				Optional:            true,
+			},
+			"wait_for_machines_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration to wait for machines when `wait_for_machines` is set, as a duration like `15m`. Defaults to `10m`, the apply fails with the machines still missing once it expires",
+				Optional:            true,
+			},
+			"ready_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration to wait for the control plane to be ready when the cluster is created, restores included (default `30m`)",
+				Optional:            true,
+			},
+			"manifest_sync":     manifestSyncSchema(),
+			"sensitive_patches": sensitivePatchesSchema(),
+			"etcd_backup":       etcdBackupSchema(),
+			"restore_from":      restoreFromSchema(),
 			"delete_machine_links": schema.BoolAttribute{
 				MarkdownDescription: "When destroying a cluster, delete machine links too",
 				Optional:            true,
 			},
+			"phase": schema.StringAttribute{
+				MarkdownDescription: "Cluster phase reported by Omni (SCALING_UP, RUNNING, ...)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the cluster is ready",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"machines_total": schema.Int64Attribute{
+				MarkdownDescription: "Number of machines in the cluster",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"machines_healthy": schema.Int64Attribute{
+				MarkdownDescription: "Number of healthy machines in the cluster",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"controlplane_machines_total": schema.Int64Attribute{
+				MarkdownDescription: "Number of control plane machines",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"controlplane_machines_healthy": schema.Int64Attribute{
+				MarkdownDescription: "Number of healthy control plane machines",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"worker_machines_total": schema.Int64Attribute{
+				MarkdownDescription: "Number of worker machines",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"worker_machines_healthy": schema.Int64Attribute{
+				MarkdownDescription: "Number of healthy worker machines",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"talos_version": schema.StringAttribute{
+				MarkdownDescription: "Talos version currently running on the cluster",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"kubernetes_version": schema.StringAttribute{
+				MarkdownDescription: "Kubernetes version currently running on the cluster",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"kubernetes_api_url": schema.StringAttribute{
+				MarkdownDescription: "Kubernetes API endpoint of the cluster",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cluster_uuid": schema.StringAttribute{
+				MarkdownDescription: "Cluster UUID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_manifest_sync": manifestSyncSummarySchema(),
 		},
 	}
 }
@@ -104,26 +254,75 @@ func (r *OmniClusterResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	err := r.client.SyncClusterAndWaitForReady(strings.NewReader(data.Template.ValueString()))
-	if err != nil {
-		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to sync cluster, got error: %s", err))
+	policy, diags := manifestSyncPolicyFrom(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	syncTemplate, diags := r.syncTemplate(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	name, err := r.client.GetClusterNameFromTemplate(strings.NewReader(data.Template.ValueString()))
+	name, err := r.client.GetClusterNameFromTemplate(strings.NewReader(syncTemplate))
 	if err != nil {
 		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster name, got error: %s", err))
 		return
 	}
 
-	template, err := r.client.GetTemplateFromClusterName(name)
+	resp.Diagnostics.Append(r.checkTemplateMachines(ctx, name, syncTemplate, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readyTimeout, err := clusterReadyTimeout(data)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ready_timeout"), "Invalid Attribute Value", err.Error())
+		return
+	}
+
+	err = r.client.SyncClusterAndWaitForReady(strings.NewReader(syncTemplate), readyTimeout)
 	if err != nil {
-		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster template, got error: %s", err))
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to sync cluster, got error: %s", err))
+		return
+	}
+
+	template, diags := r.computedTemplate(ctx, name, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	data.TemplateComputed = types.StringValue(template)
 	data.ID = types.StringValue(name)
+	data.LastManifestSync = types.ObjectNull(manifestSyncSummaryAttrTypes)
+
+	if policy.syncOnCreate() {
+		results, err := r.client.SyncManifests(name, false, policy.timeout)
+		resp.Diagnostics.Append(manifestSyncDiagnostics(results, err)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		summary, diags := manifestSyncSummary(ctx, results)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.LastManifestSync = summary
+	}
+
+	if err := r.readClusterStatus(name, &data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster status, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.readEtcdBackup(ctx, data.ID.ValueString(), &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
@@ -143,14 +342,34 @@ func (r *OmniClusterResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	template, err := r.client.GetTemplateFromClusterName(data.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster template, got error: %s", err))
+	if _, err := r.client.GetCluster(data.ID.ValueString()); err != nil {
+		if omniapi.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read cluster, got error: %s", err))
+		return
+	}
+
+	template, diags := r.computedTemplate(ctx, data.ID.ValueString(), data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	data.TemplateComputed = types.StringValue(template)
 
+	if err := r.readClusterStatus(data.ID.ValueString(), &data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster status, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.readEtcdBackup(ctx, data.ID.ValueString(), &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -173,7 +392,20 @@ func (r *OmniClusterResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	if yes, err := isClusterChangingName(data.Template.ValueString(), state.Template.ValueString()); yes || err != nil {
+	if !data.RestoreFrom.IsNull() && !data.RestoreFrom.Equal(state.RestoreFrom) {
+		resp.Diagnostics.AddAttributeError(path.Root("restore_from"), "Changing restore_from is not possible", "restore_from is only honored when the cluster is created, need to destroy resource before create it again")
+		return
+	}
+
+	planTemplate, diags := renderedTemplate(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	stateTemplate, diags := renderedTemplate(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if yes, err := isClusterChangingName(planTemplate, stateTemplate); yes || err != nil {
 		if err != nil {
 			resp.Diagnostics.AddError("Error parsing template", "Problem with YAML parsing")
 			return
@@ -182,34 +414,77 @@ func (r *OmniClusterResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	err := r.client.SyncCluster(strings.NewReader(data.Template.ValueString()))
+	policy, diags := manifestSyncPolicyFrom(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	syncManifests, err := policy.syncOnUpdate(stateTemplate, planTemplate)
 	if err != nil {
-		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to sync cluster, got error: %s", err))
+		resp.Diagnostics.AddError("Error parsing template", fmt.Sprintf("unable to compare kubernetes versions, got error: %s", err))
+		return
+	}
+
+	syncTemplate, diags := r.syncTemplate(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	name, err := r.client.GetClusterNameFromTemplate(strings.NewReader(data.Template.ValueString()))
+	name, err := r.client.GetClusterNameFromTemplate(strings.NewReader(syncTemplate))
 	if err != nil {
 		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster name, got error: %s", err))
 		return
 	}
 
-	if data.ForceManifestUpdating.ValueBool() {
-		if err := r.client.SyncManifests(name); err != nil {
-			resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to force sync manifests, got error: %s", err))
+	resp.Diagnostics.Append(r.checkTemplateMachines(ctx, name, syncTemplate, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err = r.client.SyncCluster(strings.NewReader(syncTemplate))
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to sync cluster, got error: %s", err))
+		return
+	}
+
+	data.LastManifestSync = state.LastManifestSync
+
+	if syncManifests {
+		results, err := r.client.SyncManifests(name, false, policy.timeout)
+		resp.Diagnostics.Append(manifestSyncDiagnostics(results, err)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
+
+		summary, diags := manifestSyncSummary(ctx, results)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.LastManifestSync = summary
 	}
 
-	template, err := r.client.GetTemplateFromClusterName(name)
-	if err != nil {
-		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster template, got error: %s", err))
+	template, diags := r.computedTemplate(ctx, name, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	data.TemplateComputed = types.StringValue(template)
 	data.ID = types.StringValue(name)
 
+	if err := r.readClusterStatus(name, &data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster status, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.readEtcdBackup(ctx, data.ID.ValueString(), &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -224,7 +499,13 @@ func (r *OmniClusterResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	name, err := r.client.GetClusterNameFromTemplate(strings.NewReader(data.Template.ValueString()))
+	template, diags := renderedTemplate(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name, err := r.client.GetClusterNameFromTemplate(strings.NewReader(template))
 	if err != nil {
 		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get cluster name, got error: %v", err))
 		return
@@ -251,10 +532,418 @@ func (r *OmniClusterResource) Delete(ctx context.Context, req resource.DeleteReq
 
 }
 
+func (r *OmniClusterResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniClusterResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ManifestSync.IsNull() && !data.ForceManifestUpdating.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("force_manifest_updating"), "Conflicting Attribute Configuration", "force_manifest_updating cannot be set along with manifest_sync.")
+		return
+	}
+
+	_, diags := manifestSyncPolicyFrom(ctx, data)
+	resp.Diagnostics.Append(diags...)
+
+	patches, diags := sensitivePatchesFrom(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(validateSensitivePatches(patches)...)
+	if _, err := waitForMachinesTimeout(data); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_machines_timeout"), "Invalid Attribute Value", fmt.Sprintf("wait_for_machines_timeout must be a duration like 10m: %s", err))
+	}
+	if _, err := clusterReadyTimeout(data); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ready_timeout"), "Invalid Attribute Value", fmt.Sprintf("ready_timeout must be a duration like 30m: %s", err))
+	}
+
+	etcdBackup, diags := etcdBackupFrom(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(validateEtcdBackup(etcdBackup)...)
+
+	// Only variable names matter to find unresolved placeholders, values may still be unknown.
+	if !data.Template.IsUnknown() && !data.TemplateVars.IsNull() && !data.TemplateVars.IsUnknown() {
+		vars := map[string]string{}
+		for name := range data.TemplateVars.Elements() {
+			vars[name] = ""
+		}
+
+		if _, unresolved := renderTemplateVars(data.Template.ValueString(), vars); len(unresolved) > 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("template"), "Unresolved Template Variables",
+				fmt.Sprintf("template references variables missing from template_vars: %s", strings.Join(unresolved, ", ")))
+		}
+	}
+}
+
+func (r *OmniClusterResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) || r.client == nil {
+		return
+	}
+
+	var data OmniClusterResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	etcdBackup, diags := etcdBackupFrom(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if etcdBackup != nil && etcdBackup.Enabled.ValueBool() {
+		resp.Diagnostics.Append(r.checkEtcdBackupStore()...)
+	}
+
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state OmniClusterResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The status changes along with the cluster, it is only carried over from the state while the cluster is left untouched.
+	for _, status := range clusterStatusAttributes {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(status.name), status.unknown)...)
+	}
+
+	// Without etcd_backup, the backup configuration comes from the template which may change it.
+	var etcdBackupConfig types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("etcd_backup"), &etcdBackupConfig)...)
+	if etcdBackupConfig.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("etcd_backup"), types.ObjectUnknown(etcdBackupAttrTypes))...)
+	}
+
+	// restore_from may be dropped once the cluster exists, the control plane keeps its bootstrap spec.
+	if !data.RestoreFrom.IsUnknown() && !data.RestoreFrom.IsNull() && !data.RestoreFrom.Equal(state.RestoreFrom) {
+		resp.Diagnostics.AddAttributeError(path.Root("restore_from"), "Changing restore_from is not possible", "restore_from is only honored when the cluster is created, need to destroy resource before create it again")
+		return
+	}
+
+	// Manifests are only dry-run for updates of an existing cluster.
+	if data.Template.IsUnknown() || data.TemplateVars.IsUnknown() {
+		return
+	}
+
+	policy, diags := manifestSyncPolicyFrom(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !policy.dryRun {
+		return
+	}
+
+	planTemplate, diags := renderedTemplate(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	stateTemplate, diags := renderedTemplate(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	syncManifests, err := policy.syncOnUpdate(stateTemplate, planTemplate)
+	if err != nil || !syncManifests {
+		return
+	}
+
+	// The dry-run runs against the current Kubernetes version, its diffs would not match the manifests of the new one.
+	stateVersion, err := clusterKubernetesVersion(stateTemplate)
+	if err != nil {
+		return
+	}
+	planVersion, err := clusterKubernetesVersion(planTemplate)
+	if err != nil {
+		return
+	}
+	if stateVersion != planVersion {
+		resp.Diagnostics.AddWarning("Manifests dry-run skipped",
+			fmt.Sprintf("Kubernetes manifests are synced after the upgrade from %s to %s, their diffs are not known before", stateVersion, planVersion))
+		return
+	}
+
+	results, err := r.client.SyncManifests(state.ID.ValueString(), true, policy.timeout)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to dry-run manifests sync", err.Error())
+		return
+	}
+
+	for _, res := range results {
+		if !res.Rollout && !res.Skipped {
+			resp.Diagnostics.AddWarning(fmt.Sprintf("Kubernetes manifest %s will be updated", res.Path), res.Diff)
+		}
+	}
+}
+
 func (r *OmniClusterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+// syncTemplate returns the template sent to Omni: rendered with template_vars, etcd_backup and
+// restore_from, with the sensitive patches merged in.
+func (r *OmniClusterResource) syncTemplate(ctx context.Context, data OmniClusterResourceModel) (string, diag.Diagnostics) {
+	patches, diags := sensitivePatchesFrom(ctx, data)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	rendered, d := renderedTemplate(ctx, data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	etcdBackup, d := etcdBackupFrom(ctx, data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	rendered, err := renderEtcdBackup(rendered, etcdBackup)
+	if err != nil {
+		diags.AddError("Error rendering etcd backup settings", err.Error())
+		return "", diags
+	}
+
+	restoreFrom, d := restoreFromFrom(ctx, data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	// The bootstrap spec of the control plane is immutable, so it is rendered on every sync
+	// once set at creation, out of the live cluster when restore_from was dropped.
+	if restoreFrom == nil && !data.ID.IsNull() && !data.ID.IsUnknown() {
+		clusterUUID, snapshot, err := r.client.GetClusterBootstrapSpec(data.ID.ValueString())
+		if err != nil && !omniapi.IsNotFound(err) {
+			diags.AddError("client Error", fmt.Sprintf("unable to get control plane bootstrap spec, got error: %s", err))
+			return "", diags
+		}
+
+		if snapshot != "" {
+			restoreFrom = &RestoreFromModel{ClusterUUID: types.StringValue(clusterUUID), BackupID: types.StringValue(snapshot)}
+		}
+	}
+
+	rendered, err = renderRestoreFrom(rendered, restoreFrom)
+	if err != nil {
+		diags.AddError("Error rendering restore_from", err.Error())
+		return "", diags
+	}
+
+	template, err := mergeSensitivePatches(rendered, patches)
+	if err != nil {
+		diags.AddError("Error merging sensitive patches", err.Error())
+	}
+
+	return template, diags
+}
+
+// computedTemplate exports the cluster template from Omni, with the sensitive patches redacted.
+func (r *OmniClusterResource) computedTemplate(ctx context.Context, name string, data OmniClusterResourceModel) (string, diag.Diagnostics) {
+	patches, diags := sensitivePatchesFrom(ctx, data)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	template, err := r.client.GetTemplateFromClusterName(name)
+	if err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to get cluster template, got error: %s", err))
+		return "", diags
+	}
+
+	template, err = redactSensitivePatches(template, patches)
+	if err != nil {
+		diags.AddError("Error redacting sensitive patches", err.Error())
+	}
+
+	return template, diags
+}
+
+// waitForMachinesTimeout returns the wait_for_machines_timeout duration, 10 minutes by default.
+func waitForMachinesTimeout(data OmniClusterResourceModel) (time.Duration, error) {
+	if data.WaitForMachinesTimeout.IsNull() || data.WaitForMachinesTimeout.IsUnknown() {
+		return defaultWaitForMachinesTimeout, nil
+	}
+
+	return time.ParseDuration(data.WaitForMachinesTimeout.ValueString())
+}
+
+func clusterReadyTimeout(data OmniClusterResourceModel) (time.Duration, error) {
+	if data.ReadyTimeout.IsNull() || data.ReadyTimeout.IsUnknown() {
+		return defaultClusterReadyTimeout, nil
+	}
+
+	return time.ParseDuration(data.ReadyTimeout.ValueString())
+}
+
+// checkTemplateMachines makes sure every machine referenced by the template is registered and connected
+// to Omni and not allocated to another cluster. With wait_for_machines, it polls until the timeout expires.
+func (r *OmniClusterResource) checkTemplateMachines(ctx context.Context, cluster, template string, data OmniClusterResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	uuids, err := templateMachines(template)
+	if err != nil {
+		diags.AddError("Error parsing template", fmt.Sprintf("unable to list template machines, got error: %s", err))
+		return diags
+	}
+	if len(uuids) == 0 {
+		return diags
+	}
+
+	timeout, err := waitForMachinesTimeout(data)
+	if err != nil {
+		diags.AddAttributeError(path.Root("wait_for_machines_timeout"), "Invalid Attribute Value", err.Error())
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		allocations, err := r.client.GetMachineAllocations(uuids)
+		if err != nil {
+			diags.AddError("client Error", fmt.Sprintf("unable to get machines, got error: %s", err))
+			return diags
+		}
+
+		var problems diag.Diagnostics
+		for _, uuid := range uuids {
+			allocation := allocations[uuid]
+			switch {
+			case !allocation.Registered:
+				problems.AddAttributeError(path.Root("template"), "Unknown Machine",
+					fmt.Sprintf("machine %s is not registered in Omni", uuid))
+			case !allocation.Connected:
+				problems.AddAttributeError(path.Root("template"), "Machine Not Connected",
+					fmt.Sprintf("machine %s is not connected to Omni", uuid))
+			case allocation.Cluster != "" && allocation.Cluster != cluster:
+				problems.AddAttributeError(path.Root("template"), "Machine Already Allocated",
+					fmt.Sprintf("machine %s is already allocated to cluster %s", uuid, allocation.Cluster))
+			}
+		}
+
+		if !problems.HasError() {
+			return diags
+		}
+
+		if !data.WaitForMachines.ValueBool() {
+			diags.Append(problems...)
+			return diags
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.Append(problems...)
+			return diags
+		case <-ticker.C:
+		}
+	}
+}
+
+// clusterStatusAttributes are the computed attributes of the cluster status which an update changes.
+var clusterStatusAttributes = []struct {
+	name    string
+	unknown attr.Value
+}{
+	{"phase", types.StringUnknown()},
+	{"ready", types.BoolUnknown()},
+	{"machines_total", types.Int64Unknown()},
+	{"machines_healthy", types.Int64Unknown()},
+	{"controlplane_machines_total", types.Int64Unknown()},
+	{"controlplane_machines_healthy", types.Int64Unknown()},
+	{"worker_machines_total", types.Int64Unknown()},
+	{"worker_machines_healthy", types.Int64Unknown()},
+	{"talos_version", types.StringUnknown()},
+	{"kubernetes_version", types.StringUnknown()},
+}
+
+// readClusterStatus fills the computed status attributes from ClusterStatus and related resources.
+// Omni creates these resources asynchronously, so the ones not created yet leave their attributes null.
+func (r *OmniClusterResource) readClusterStatus(name string, data *OmniClusterResourceModel) error {
+	data.Phase = types.StringNull()
+	data.Ready = types.BoolNull()
+	data.MachinesTotal = types.Int64Null()
+	data.MachinesHealthy = types.Int64Null()
+
+	status, err := r.client.GetClusterStatus(name)
+	if err != nil && !omniapi.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		spec := status.TypedSpec().Value
+		data.Phase = types.StringValue(spec.Phase.String())
+		data.Ready = types.BoolValue(spec.Ready)
+		data.MachinesTotal = types.Int64Value(int64(spec.GetMachines().GetTotal()))
+		data.MachinesHealthy = types.Int64Value(int64(spec.GetMachines().GetHealthy()))
+	}
+
+	machineSets, err := r.client.GetClusterMachineSetStatuses(name)
+	if err != nil {
+		return err
+	}
+
+	var cpTotal, cpHealthy, workerTotal, workerHealthy int64
+	machineSets.ForEach(func(ms *omni.MachineSetStatus) {
+		machines := ms.TypedSpec().Value.GetMachines()
+		if _, ok := ms.Metadata().Labels().Get(omni.LabelControlPlaneRole); ok {
+			cpTotal += int64(machines.GetTotal())
+			cpHealthy += int64(machines.GetHealthy())
+		} else {
+			workerTotal += int64(machines.GetTotal())
+			workerHealthy += int64(machines.GetHealthy())
+		}
+	})
+	data.ControlPlaneMachinesTotal = types.Int64Value(cpTotal)
+	data.ControlPlaneMachinesHealthy = types.Int64Value(cpHealthy)
+	data.WorkerMachinesTotal = types.Int64Value(workerTotal)
+	data.WorkerMachinesHealthy = types.Int64Value(workerHealthy)
+
+	data.TalosVersion = types.StringNull()
+	talos, err := r.client.GetTalosUpgradeStatus(name)
+	if err != nil && !omniapi.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		data.TalosVersion = types.StringValue(talos.TypedSpec().Value.LastUpgradeVersion)
+	}
+
+	data.KubernetesVersion = types.StringNull()
+	kubernetes, err := r.client.GetKubernetesUpgradeStatus(name)
+	if err != nil && !omniapi.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		data.KubernetesVersion = types.StringValue(kubernetes.TypedSpec().Value.LastUpgradeVersion)
+	}
+
+	data.KubernetesAPIURL = types.StringNull()
+	url, err := r.client.GetKubernetesAPIURL(name)
+	if err != nil && !omniapi.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		data.KubernetesAPIURL = types.StringValue(url)
+	}
+
+	data.ClusterUUID = types.StringNull()
+	uuid, err := r.client.GetClusterUUID(name)
+	if err != nil && !omniapi.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		data.ClusterUUID = types.StringValue(uuid)
+	}
+
+	return nil
+}
+
 func isClusterChangingName(planTemplate, stateTemplate string) (bool, error) {
 	T := struct {
 		Kind string
@@ -26,6 +26,10 @@ kubernetes:
   version: "v1.27.12"
 talos:
   version: "v1.6.8"`),
+					resource.TestCheckResourceAttr("omni_cluster.test", "ready", "true"),
+					resource.TestCheckResourceAttr("omni_cluster.test", "phase", "RUNNING"),
+					resource.TestCheckResourceAttrSet("omni_cluster.test", "cluster_uuid"),
+					resource.TestCheckResourceAttrSet("omni_cluster.test", "kubernetes_api_url"),
 				),
 			},
 			// ImportState testing
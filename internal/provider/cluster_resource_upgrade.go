@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithUpgradeState = &OmniClusterResource{}
+
+// omniClusterSchemaVersion is the current omni_cluster schema version.
+// Bump it and add an upgrader below whenever an attribute is renamed, removed or changes type.
+const omniClusterSchemaVersion = 1
+
+// OmniClusterResourceModelV0 describes the omni_cluster data model of schema version 0.
+type OmniClusterResourceModelV0 struct {
+	Template              types.String `tfsdk:"template"`
+	TemplateComputed      types.String `tfsdk:"template_computed"`
+	ID                    types.String `tfsdk:"id"`
+	ForceManifestUpdating types.Bool   `tfsdk:"force_manifest_updating"`
+	DeleteMachineLinks    types.Bool   `tfsdk:"delete_machine_links"`
+}
+
+func (r *OmniClusterResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"template":                schema.StringAttribute{Required: true},
+					"template_computed":       schema.StringAttribute{Computed: true},
+					"id":                      schema.StringAttribute{Computed: true},
+					"force_manifest_updating": schema.BoolAttribute{Optional: true},
+					"delete_machine_links":    schema.BoolAttribute{Optional: true},
+				},
+			},
+			StateUpgrader: upgradeOmniClusterStateV0,
+		},
+	}
+}
+
+// upgradeOmniClusterStateV0 keeps every version 0 attribute. Status attributes are left
+// null and get populated by the next refresh.
+func upgradeOmniClusterStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior OmniClusterResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data := OmniClusterResourceModel{
+		Template:              prior.Template,
+		TemplateVars:          types.MapNull(types.StringType),
+		TemplateComputed:      prior.TemplateComputed,
+		ID:                    prior.ID,
+		ForceManifestUpdating: prior.ForceManifestUpdating,
+		DeleteMachineLinks:    prior.DeleteMachineLinks,
+		ManifestSync:          types.ObjectNull(manifestSyncAttrTypes),
+		SensitivePatches:      types.ListNull(types.ObjectType{AttrTypes: sensitivePatchAttrTypes}),
+		EtcdBackup:            types.ObjectNull(etcdBackupAttrTypes),
+		RestoreFrom:           types.ObjectNull(restoreFromAttrTypes),
+		LastManifestSync:      types.ObjectNull(manifestSyncSummaryAttrTypes),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
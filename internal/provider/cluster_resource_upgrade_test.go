@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testUpgradeState runs the upgrader of version on the JSON state stored in fixture
+// and returns the upgraded state.
+func testUpgradeState(t *testing.T, r resource.Resource, version int64, fixture string) tfsdk.State {
+	t.Helper()
+
+	ctx := context.Background()
+
+	raw, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("unable to read fixture: %v", err)
+	}
+
+	upgrader, ok := r.(resource.ResourceWithUpgradeState).UpgradeState(ctx)[version]
+	if !ok {
+		t.Fatalf("no state upgrader for version %d", version)
+	}
+
+	prior, err := (&tfprotov6.RawState{JSON: raw}).Unmarshal(upgrader.PriorSchema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("unable to load fixture with the prior schema: %v", err)
+	}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: prior},
+	}
+	resp := &resource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	upgrader.StateUpgrader(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return resp.State
+}
+
+func TestOmniClusterResourceUpgradeStateV0(t *testing.T) {
+	state := testUpgradeState(t, NewOmniClusterResource(), 0, "testdata/omni_cluster_state_v0.json")
+
+	var data OmniClusterResourceModel
+	if diags := state.Get(context.Background(), &data); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if data.ID.ValueString() != "omni-cluster-1" {
+		t.Errorf("expected id omni-cluster-1, got %q", data.ID.ValueString())
+	}
+	if !data.DeleteMachineLinks.ValueBool() {
+		t.Error("expected delete_machine_links to be kept")
+	}
+	if !data.ForceManifestUpdating.IsNull() {
+		t.Error("expected force_manifest_updating to stay null")
+	}
+	if data.TemplateComputed.IsNull() || data.Template.IsNull() {
+		t.Error("expected templates to be kept")
+	}
+	if !data.ManifestSync.IsNull() || !data.LastManifestSync.IsNull() || !data.Phase.IsNull() {
+		t.Error("expected attributes added in version 1 to be null")
+	}
+}
@@ -0,0 +1,417 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultWorkersName = "workers"
+
+// templateVarPattern matches the {{ name }} placeholders substituted with template_vars.
+var templateVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// renderTemplateVars substitutes every {{ name }} placeholder of template with vars[name].
+// Placeholders without a matching variable are left untouched and returned as unresolved.
+func renderTemplateVars(template string, vars map[string]string) (string, []string) {
+	var unresolved []string
+	seen := map[string]struct{}{}
+
+	rendered := templateVarPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := templateVarPattern.FindStringSubmatch(placeholder)[1]
+
+		if value, ok := vars[name]; ok {
+			return value
+		}
+
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			unresolved = append(unresolved, name)
+		}
+
+		return placeholder
+	})
+
+	return rendered, unresolved
+}
+
+// renderedTemplate returns the template with template_vars substituted. Templates are
+// only rendered when template_vars is set, so literal braces keep working otherwise.
+func renderedTemplate(ctx context.Context, data OmniClusterResourceModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if data.TemplateVars.IsNull() {
+		return data.Template.ValueString(), diags
+	}
+
+	vars := map[string]string{}
+	diags.Append(data.TemplateVars.ElementsAs(ctx, &vars, false)...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	rendered, unresolved := renderTemplateVars(data.Template.ValueString(), vars)
+	if len(unresolved) > 0 {
+		diags.AddAttributeError(path.Root("template"), "Unresolved Template Variables",
+			fmt.Sprintf("template references variables missing from template_vars: %s", strings.Join(unresolved, ", ")))
+	}
+
+	return rendered, diags
+}
+
+// SensitivePatchModel describes an element of the sensitive_patches attribute of omni_cluster.
+type SensitivePatchModel struct {
+	Kind   types.String `tfsdk:"kind"`
+	Target types.String `tfsdk:"target"`
+	Name   types.String `tfsdk:"name"`
+	Inline types.String `tfsdk:"inline"`
+}
+
+var sensitivePatchAttrTypes = map[string]attr.Type{
+	"kind":   types.StringType,
+	"target": types.StringType,
+	"name":   types.StringType,
+	"inline": types.StringType,
+}
+
+func sensitivePatchesSchema() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		MarkdownDescription: "Patches merged into the template at sync time and redacted from `template_computed`",
+		Optional:            true,
+		Sensitive:           true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"kind": schema.StringAttribute{
+					MarkdownDescription: "Kind of the template document to patch: `Cluster`, `ControlPlane`, `Workers` or `Machine`",
+					Required:            true,
+				},
+				"target": schema.StringAttribute{
+					MarkdownDescription: "Name of the Workers machine set (defaults to `workers`) or UUID of the Machine",
+					Optional:            true,
+				},
+				"name": schema.StringAttribute{
+					MarkdownDescription: "Patch name",
+					Required:            true,
+				},
+				"inline": schema.StringAttribute{
+					MarkdownDescription: "Patch content in YAML",
+					Required:            true,
+				},
+			},
+		},
+	}
+}
+
+// validateSensitivePatches checks the sensitive_patches attribute without needing the template.
+func validateSensitivePatches(patches []SensitivePatchModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, patch := range patches {
+		p := path.Root("sensitive_patches").AtListIndex(i)
+
+		switch patch.Kind.ValueString() {
+		case "Cluster", "ControlPlane", "Workers":
+		case "Machine":
+			if patch.Target.IsNull() {
+				diags.AddAttributeError(p.AtName("target"), "Missing Attribute Configuration", "target must be set to the machine UUID when kind is Machine.")
+			}
+		default:
+			if !patch.Kind.IsUnknown() {
+				diags.AddAttributeError(p.AtName("kind"), "Invalid Attribute Value", fmt.Sprintf("kind must be one of Cluster, ControlPlane, Workers or Machine, got %q", patch.Kind.ValueString()))
+			}
+		}
+
+		if patch.Inline.IsUnknown() || patch.Inline.IsNull() {
+			continue
+		}
+
+		var content map[string]any
+		if err := yaml.Unmarshal([]byte(patch.Inline.ValueString()), &content); err != nil || content == nil {
+			// The content itself is sensitive and is never part of the message.
+			diags.AddAttributeError(p.AtName("inline"), "Invalid Attribute Value", fmt.Sprintf("patch %q must be a YAML mapping", patch.Name.ValueString()))
+		}
+	}
+
+	return diags
+}
+
+// sensitivePatchesFrom reads the sensitive_patches attribute.
+func sensitivePatchesFrom(ctx context.Context, data OmniClusterResourceModel) ([]SensitivePatchModel, diag.Diagnostics) {
+	var patches []SensitivePatchModel
+
+	if data.SensitivePatches.IsNull() || data.SensitivePatches.IsUnknown() {
+		return nil, nil
+	}
+
+	diags := data.SensitivePatches.ElementsAs(ctx, &patches, false)
+
+	return patches, diags
+}
+
+// templateDocuments decodes every YAML document of a template.
+func templateDocuments(template string) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+
+	d := yaml.NewDecoder(strings.NewReader(template))
+	for {
+		var doc yaml.Node
+
+		err := d.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+			docs = append(docs, &doc)
+		}
+	}
+}
+
+// encodeTemplateDocuments encodes the documents back into a multi-document template.
+func encodeTemplateDocuments(docs []*yaml.Node) (string, error) {
+	buf := &bytes.Buffer{}
+
+	e := yaml.NewEncoder(buf)
+	e.SetIndent(2)
+
+	for _, doc := range docs {
+		if err := e.Encode(doc); err != nil {
+			return "", err
+		}
+	}
+
+	if err := e.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// mappingValue returns the value node of key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func removeMappingKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func mappingScalar(mapping *yaml.Node, key string) string {
+	if v := mappingValue(mapping, key); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value
+	}
+
+	return ""
+}
+
+// isPatchTarget tells whether a template document is the one addressed by kind and target.
+func isPatchTarget(doc *yaml.Node, kind, target string) bool {
+	m := doc.Content[0]
+
+	if mappingScalar(m, "kind") != kind {
+		return false
+	}
+
+	switch kind {
+	case "Workers":
+		name := mappingScalar(m, "name")
+		if name == "" {
+			name = defaultWorkersName
+		}
+		if target == "" {
+			target = defaultWorkersName
+		}
+		return name == target
+	case "Machine":
+		return mappingScalar(m, "name") == target
+	default:
+		return true
+	}
+}
+
+// mergeSensitivePatches appends the sensitive patches to the patches of the documents they target.
+func mergeSensitivePatches(template string, patches []SensitivePatchModel) (string, error) {
+	if len(patches) == 0 {
+		return template, nil
+	}
+
+	docs, err := templateDocuments(template)
+	if err != nil {
+		return "", err
+	}
+
+	for _, patch := range patches {
+		var inline yaml.Node
+		if err := yaml.Unmarshal([]byte(patch.Inline.ValueString()), &inline); err != nil || len(inline.Content) == 0 {
+			return "", fmt.Errorf("sensitive patch %q is not valid YAML", patch.Name.ValueString())
+		}
+
+		var doc *yaml.Node
+		for _, d := range docs {
+			if isPatchTarget(d, patch.Kind.ValueString(), patch.Target.ValueString()) {
+				doc = d
+				break
+			}
+		}
+		if doc == nil {
+			return "", fmt.Errorf("sensitive patch %q: no %s %s document in template", patch.Name.ValueString(), patch.Kind.ValueString(), patch.Target.ValueString())
+		}
+
+		m := doc.Content[0]
+		list := mappingValue(m, "patches")
+		if list == nil {
+			list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "patches"}, list)
+		}
+
+		list.Content = append(list.Content, &yaml.Node{
+			Kind: yaml.MappingNode,
+			Tag:  "!!map",
+			Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: "name"},
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: patch.Name.ValueString()},
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: "inline"},
+				inline.Content[0],
+			},
+		})
+	}
+
+	return encodeTemplateDocuments(docs)
+}
+
+// redactSensitivePatches removes the sensitive patches from a template exported by Omni: a patch is
+// removed from the document the sensitive patch targets when it has its name and its content, a
+// same-named patch of the template itself is kept. Exported patches carry their name in the name annotation.
+func redactSensitivePatches(template string, patches []SensitivePatchModel) (string, error) {
+	if len(patches) == 0 {
+		return template, nil
+	}
+
+	docs, err := templateDocuments(template)
+	if err != nil {
+		return "", err
+	}
+
+	for _, doc := range docs {
+		inlines := map[string][]string{}
+		for _, patch := range patches {
+			if isPatchTarget(doc, patch.Kind.ValueString(), patch.Target.ValueString()) {
+				inlines[patch.Name.ValueString()] = append(inlines[patch.Name.ValueString()], patch.Inline.ValueString())
+			}
+		}
+		if len(inlines) == 0 {
+			continue
+		}
+
+		m := doc.Content[0]
+
+		list := mappingValue(m, "patches")
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		kept := list.Content[:0]
+		for _, p := range list.Content {
+			name := mappingScalar(p, "name")
+			if annotations := mappingValue(p, "annotations"); annotations != nil && name == "" {
+				name = mappingScalar(annotations, "name")
+			}
+
+			if !isSensitivePatch(p, inlines[name]) {
+				kept = append(kept, p)
+			}
+		}
+		list.Content = kept
+
+		if len(kept) == 0 {
+			removeMappingKey(m, "patches")
+		}
+	}
+
+	return encodeTemplateDocuments(docs)
+}
+
+// isSensitivePatch tells whether the inline content of an exported patch is one of the sensitive ones.
+func isSensitivePatch(patch *yaml.Node, inlines []string) bool {
+	inline := mappingValue(patch, "inline")
+	if inline == nil || len(inlines) == 0 {
+		return false
+	}
+
+	data, err := yaml.Marshal(inline)
+	if err != nil {
+		return false
+	}
+
+	for _, sensitive := range inlines {
+		if yamlEqual(string(data), sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// templateMachines returns the UUIDs of every machine referenced by a template, through
+// Machine documents or the machines list of ControlPlane and Workers documents.
+func templateMachines(template string) ([]string, error) {
+	docs, err := templateDocuments(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var machines []string
+	seen := map[string]struct{}{}
+
+	add := func(id string) {
+		if _, ok := seen[id]; id != "" && !ok {
+			seen[id] = struct{}{}
+			machines = append(machines, id)
+		}
+	}
+
+	for _, doc := range docs {
+		m := doc.Content[0]
+
+		switch mappingScalar(m, "kind") {
+		case "Machine":
+			add(mappingScalar(m, "name"))
+		case "ControlPlane", "Workers":
+			if list := mappingValue(m, "machines"); list != nil && list.Kind == yaml.SequenceNode {
+				for _, item := range list.Content {
+					add(item.Value)
+				}
+			}
+		}
+	}
+
+	return machines, nil
+}
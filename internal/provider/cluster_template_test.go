@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testClusterTemplate = `kind: Cluster
+name: omni-cluster-1
+kubernetes:
+  version: "v1.29.9"
+talos:
+  version: "v1.7.7"
+---
+kind: ControlPlane
+machines:
+  - "d7413242-47ce-2140-0eee-cefb3e72d13e"
+---
+kind: Workers
+name: gpu
+machines:
+  - "954d3242-0d82-0cb3-f8de-7d3342c2051d"
+`
+
+func TestMergeSensitivePatches(t *testing.T) {
+	patches := []SensitivePatchModel{
+		{
+			Kind:   types.StringValue("Cluster"),
+			Name:   types.StringValue("registry-auth"),
+			Inline: types.StringValue("machine:\n  registries:\n    config:\n      ghcr.io:\n        auth:\n          password: s3cr3t\n"),
+		},
+		{
+			Kind:   types.StringValue("Workers"),
+			Target: types.StringValue("gpu"),
+			Name:   types.StringValue("gpu-token"),
+			Inline: types.StringValue("machine:\n  env:\n    TOKEN: s3cr3t\n"),
+		},
+	}
+
+	merged, err := mergeSensitivePatches(testClusterTemplate, patches)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Count(merged, "s3cr3t") != 2 {
+		t.Errorf("expected both patches to be merged, got:\n%s", merged)
+	}
+
+	redacted, err := redactSensitivePatches(merged, patches)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(redacted, "s3cr3t") || strings.Contains(redacted, "patches") {
+		t.Errorf("expected sensitive patches to be redacted, got:\n%s", redacted)
+	}
+	if !strings.Contains(redacted, "name: gpu") {
+		t.Errorf("expected the rest of the template to be kept, got:\n%s", redacted)
+	}
+
+	_, err = mergeSensitivePatches(testClusterTemplate, []SensitivePatchModel{{
+		Kind:   types.StringValue("Machine"),
+		Target: types.StringValue("00000000-0000-0000-0000-000000000000"),
+		Name:   types.StringValue("unknown"),
+		Inline: types.StringValue("machine: {}"),
+	}})
+	if err == nil {
+		t.Error("expected an error for a patch targeting a machine missing from the template")
+	}
+}
+
+func TestRedactExportedSensitivePatches(t *testing.T) {
+	exported := `kind: Cluster
+name: omni-cluster-1
+patches:
+  - idOverride: 400-omni-cluster-1-registry-auth
+    annotations:
+      name: registry-auth
+    inline:
+      machine:
+        env:
+          TOKEN: s3cr3t
+  - idOverride: 401-omni-cluster-1-sysctls
+    annotations:
+      name: sysctls
+    inline:
+      machine:
+        sysctls:
+          vm.max_map_count: "262144"
+  - idOverride: 402-omni-cluster-1-registry-auth
+    annotations:
+      name: registry-auth
+    inline:
+      machine:
+        env:
+          PROXY: internal
+---
+kind: Workers
+name: workers
+patches:
+  - idOverride: 400-omni-cluster-1-workers-registry-auth
+    annotations:
+      name: registry-auth
+    inline:
+      machine:
+        env:
+          MIRROR: public
+`
+
+	redacted, err := redactSensitivePatches(exported, []SensitivePatchModel{{
+		Kind:   types.StringValue("Cluster"),
+		Name:   types.StringValue("registry-auth"),
+		Inline: types.StringValue("machine:\n  env:\n    TOKEN: s3cr3t\n"),
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(redacted, "s3cr3t") || !strings.Contains(redacted, "sysctls") {
+		t.Errorf("expected only the sensitive patch to be redacted, got:\n%s", redacted)
+	}
+	if !strings.Contains(redacted, "MIRROR: public") {
+		t.Errorf("expected the patch of the same name in another document to be kept, got:\n%s", redacted)
+	}
+	if !strings.Contains(redacted, "PROXY: internal") {
+		t.Errorf("expected the patch of the same name and another content to be kept, got:\n%s", redacted)
+	}
+}
+
+func TestRenderTemplateVars(t *testing.T) {
+	template := `kind: Cluster
+name: {{ cluster_name }}
+kubernetes:
+  version: "{{kubernetes_version}}"
+talos:
+  version: "{{ talos_version }}"
+---
+kind: Workers
+name: {{ cluster_name }}-workers
+`
+
+	rendered, unresolved := renderTemplateVars(template, map[string]string{
+		"cluster_name":       "omni-cluster-1",
+		"kubernetes_version": "v1.29.9",
+	})
+
+	if len(unresolved) != 1 || unresolved[0] != "talos_version" {
+		t.Errorf("expected talos_version to be unresolved, got %v", unresolved)
+	}
+	if !strings.Contains(rendered, "name: omni-cluster-1\n") || !strings.Contains(rendered, "name: omni-cluster-1-workers") {
+		t.Errorf("expected cluster_name to be substituted, got:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, `version: "v1.29.9"`) {
+		t.Errorf("expected kubernetes_version to be substituted, got:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "{{ talos_version }}") {
+		t.Errorf("expected unresolved placeholder to be kept, got:\n%s", rendered)
+	}
+}
+
+func TestTemplateMachines(t *testing.T) {
+	template := testClusterTemplate + `---
+kind: Machine
+name: "d7413242-47ce-2140-0eee-cefb3e72d13e"
+---
+kind: Machine
+name: "1b4c9d1e-6a7e-4f0e-9b0c-2f1a7a3c5d6e"
+`
+
+	machines, err := templateMachines(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"d7413242-47ce-2140-0eee-cefb3e72d13e",
+		"954d3242-0d82-0cb3-f8de-7d3342c2051d",
+		"1b4c9d1e-6a7e-4f0e-9b0c-2f1a7a3c5d6e",
+	}
+	if strings.Join(machines, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, machines)
+	}
+}
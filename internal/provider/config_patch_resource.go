@@ -0,0 +1,330 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/siderolabs/omni/client/pkg/constants"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniConfigPatchResource{}
+var _ resource.ResourceWithImportState = &OmniConfigPatchResource{}
+var _ resource.ResourceWithValidateConfig = &OmniConfigPatchResource{}
+
+func NewOmniConfigPatchResource() resource.Resource {
+	return &OmniConfigPatchResource{}
+}
+
+// OmniConfigPatchResource defines the resource implementation.
+type OmniConfigPatchResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniConfigPatchResourceModel describes the resource data model.
+type OmniConfigPatchResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	Weight         types.Int64  `tfsdk:"weight"`
+	Cluster        types.String `tfsdk:"cluster"`
+	MachineSet     types.String `tfsdk:"machine_set"`
+	ClusterMachine types.String `tfsdk:"cluster_machine"`
+	Machine        types.String `tfsdk:"machine"`
+	Patch          types.String `tfsdk:"patch"`
+}
+
+func (r *OmniConfigPatchResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_patch"
+}
+
+func (r *OmniConfigPatchResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_config_patch resource. Patches managed here are left untouched by the `omni_cluster` template syncs",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Config patch ID (`<weight>-tf-<target>-<name>`)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Patch name",
+				Required:            true,
+				PlanModifiers:       replace,
+			},
+			"weight": schema.Int64Attribute{
+				MarkdownDescription: "Ordering weight between 0 and 999, patches are applied by increasing weight (default 200 for clusters, 400 otherwise)",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "Name of the targeted cluster, also required with `machine_set` and `cluster_machine`",
+				Optional:            true,
+				PlanModifiers:       replace,
+			},
+			"machine_set": schema.StringAttribute{
+				MarkdownDescription: "ID of the targeted machine set",
+				Optional:            true,
+				PlanModifiers:       replace,
+			},
+			"cluster_machine": schema.StringAttribute{
+				MarkdownDescription: "UUID of the targeted machine, within `cluster`",
+				Optional:            true,
+				PlanModifiers:       replace,
+			},
+			"machine": schema.StringAttribute{
+				MarkdownDescription: "UUID of the targeted machine, whatever its cluster. Conflicts with `cluster`",
+				Optional:            true,
+				PlanModifiers:       replace,
+			},
+			"patch": schema.StringAttribute{
+				MarkdownDescription: "Patch content in YAML",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (r *OmniConfigPatchResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniConfigPatchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniConfigPatchResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(configPatchID(data))
+
+	// Creating over an existing patch would take it over from whatever owns it.
+	_, err := r.client.GetConfigPatch(data.ID.ValueString())
+	if err != nil && !omniapi.IsNotFound(err) {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read config patch, got error: %s", err))
+		return
+	}
+	if err == nil {
+		resp.Diagnostics.AddError("Resource Already Exists",
+			fmt.Sprintf("config patch %s already exists, use terraform import with the ID %s to manage it", data.ID.ValueString(), data.ID.ValueString()))
+		return
+	}
+
+	if err := r.client.ApplyConfigPatch(configPatchFromModel(data)); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create config patch, got error: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "create a resource omni_config_patch")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniConfigPatchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniConfigPatchResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	patch, err := r.client.GetConfigPatch(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read config patch, got error: %s", err))
+		return
+	}
+
+	data.Name = types.StringValue(patch.Name)
+	data.Cluster = optionalString(patch.Cluster)
+	data.MachineSet = optionalString(patch.MachineSet)
+	data.ClusterMachine = optionalString(patch.ClusterMachine)
+	data.Machine = optionalString(patch.Machine)
+
+	// Imported patches get their weight back from the ID when it is not the default one.
+	if data.Weight.IsNull() && configPatchID(data) != data.ID.ValueString() {
+		var weight int64
+		if _, err := fmt.Sscanf(data.ID.ValueString(), "%03d-", &weight); err == nil {
+			data.Weight = types.Int64Value(weight)
+		}
+	}
+
+	// Keep the configured formatting unless the patch was edited out of Terraform.
+	if !yamlEqual(data.Patch.ValueString(), patch.Data) {
+		data.Patch = types.StringValue(patch.Data)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniConfigPatchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniConfigPatchResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.ApplyConfigPatch(configPatchFromModel(data)); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to update config patch, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniConfigPatchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniConfigPatchResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteConfigPatch(data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete config patch, got error: %s", err))
+		return
+	}
+}
+
+// ImportState only imports patches created by omni_config_patch. Applying a patch adds the managed-by label,
+// which would hide a patch owned by a cluster template from that template.
+func (r *OmniConfigPatchResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	patch, err := r.client.GetConfigPatch(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read config patch, got error: %s", err))
+		return
+	}
+
+	if !patch.Managed {
+		resp.Diagnostics.AddError("Import Not Supported",
+			fmt.Sprintf("config patch %s was not created by omni_config_patch, manage it where it comes from (the cluster template or the Omni UI)", req.ID))
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniConfigPatchResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniConfigPatchResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Weight.IsNull() && !data.Weight.IsUnknown() && (data.Weight.ValueInt64() < 0 || data.Weight.ValueInt64() > 999) {
+		resp.Diagnostics.AddAttributeError(path.Root("weight"), "Invalid Attribute Value",
+			fmt.Sprintf("weight must be between 0 and 999, got %d", data.Weight.ValueInt64()))
+	}
+
+	switch {
+	case !data.Machine.IsNull():
+		if !data.Cluster.IsNull() || !data.MachineSet.IsNull() || !data.ClusterMachine.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("machine"), "Invalid Attribute Combination",
+				"machine cannot be combined with cluster, machine_set or cluster_machine")
+		}
+	case data.Cluster.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("cluster"), "Missing Attribute Configuration",
+			"one of cluster or machine must be set")
+	case !data.MachineSet.IsNull() && !data.ClusterMachine.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("machine_set"), "Invalid Attribute Combination",
+			"machine_set cannot be combined with cluster_machine")
+	}
+
+	if !data.Patch.IsUnknown() && !data.Patch.IsNull() {
+		if err := validatePatch(data.Patch.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("patch"), "Invalid Attribute Value",
+				fmt.Sprintf("patch %q: %s", data.Name.ValueString(), err))
+		}
+	}
+}
+
+// configPatchID builds the ID of a patch after its weight, its most specific target and its name.
+// The tf- prefix keeps it apart from the patches of cluster templates, machine sets and cluster machines.
+func configPatchID(data OmniConfigPatchResourceModel) string {
+	weight := int64(constants.PatchBaseWeightClusterMachine)
+	target := data.Machine.ValueString()
+
+	switch {
+	case !data.MachineSet.IsNull():
+		weight, target = constants.PatchBaseWeightMachineSet, data.MachineSet.ValueString()
+	case !data.ClusterMachine.IsNull():
+		target = data.ClusterMachine.ValueString()
+	case !data.Cluster.IsNull():
+		weight, target = constants.PatchBaseWeightCluster, data.Cluster.ValueString()
+	}
+
+	if !data.Weight.IsNull() {
+		weight = data.Weight.ValueInt64()
+	}
+
+	return fmt.Sprintf("%03d-tf-%s-%s", weight, target, data.Name.ValueString())
+}
+
+func configPatchFromModel(data OmniConfigPatchResourceModel) omniapi.ConfigPatch {
+	return omniapi.ConfigPatch{
+		ID:             data.ID.ValueString(),
+		Name:           data.Name.ValueString(),
+		Cluster:        data.Cluster.ValueString(),
+		MachineSet:     data.MachineSet.ValueString(),
+		ClusterMachine: data.ClusterMachine.ValueString(),
+		Machine:        data.Machine.ValueString(),
+		Data:           data.Patch.ValueString(),
+	}
+}
+
+func optionalString(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(s)
+}
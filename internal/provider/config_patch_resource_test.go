@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOmniConfigPatchResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccOmniConfigPatchResourceConfig("eth0"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_config_patch.test", "id", "300-tf-test-cluster-1-nic"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "omni_config_patch.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccOmniConfigPatchResourceConfig("eth1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_config_patch.test", "id", "300-tf-test-cluster-1-nic"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniConfigPatchResourceConfig(nic string) string {
+	return fmt.Sprintf(`
+resource "omni_config_patch" "test" {
+  name    = "nic"
+  weight  = 300
+  cluster = "test-cluster-1"
+  patch   = <<-EOT
+    machine:
+      network:
+        interfaces:
+          - interface: %s
+            dhcp: true
+  EOT
+}
+`, nic)
+}
+
+func TestConfigPatchID(t *testing.T) {
+	for _, tc := range []struct {
+		data     OmniConfigPatchResourceModel
+		expected string
+	}{
+		{
+			OmniConfigPatchResourceModel{Name: types.StringValue("nic"), Cluster: types.StringValue("c1")},
+			"200-tf-c1-nic",
+		},
+		{
+			OmniConfigPatchResourceModel{Name: types.StringValue("nic"), Cluster: types.StringValue("c1"), MachineSet: types.StringValue("c1-workers")},
+			"400-tf-c1-workers-nic",
+		},
+		{
+			OmniConfigPatchResourceModel{Name: types.StringValue("nic"), Machine: types.StringValue("uuid"), Weight: types.Int64Value(50)},
+			"050-tf-uuid-nic",
+		},
+	} {
+		if id := configPatchID(tc.data); id != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, id)
+		}
+	}
+}
+
+func TestValidatePatch(t *testing.T) {
+	if err := validatePatch("machine:\n  network:\n    hostname: test\n"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, patch := range []string{"- not a mapping", "machine: [", "machine:\n  unknownField: true\n"} {
+		if err := validatePatch(patch); err == nil {
+			t.Errorf("expected %q to be rejected", patch)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniEtcdBackupS3ConfigurationResource{}
+var _ resource.ResourceWithImportState = &OmniEtcdBackupS3ConfigurationResource{}
+var _ resource.ResourceWithValidateConfig = &OmniEtcdBackupS3ConfigurationResource{}
+
+func NewOmniEtcdBackupS3ConfigurationResource() resource.Resource {
+	return &OmniEtcdBackupS3ConfigurationResource{}
+}
+
+// OmniEtcdBackupS3ConfigurationResource defines the resource implementation.
+type OmniEtcdBackupS3ConfigurationResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniEtcdBackupS3ConfigurationResourceModel describes the resource data model.
+type OmniEtcdBackupS3ConfigurationResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Bucket          types.String `tfsdk:"bucket"`
+	Region          types.String `tfsdk:"region"`
+	Endpoint        types.String `tfsdk:"endpoint"`
+	AccessKeyID     types.String `tfsdk:"access_key_id"`
+	SecretAccessKey types.String `tfsdk:"secret_access_key"`
+	SessionToken    types.String `tfsdk:"session_token"`
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_etcd_backup_s3_configuration"
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_etcd_backup_s3_configuration resource. Omni has a single S3 backup store, declare this resource once; " +
+			"creating it fails when a configuration already exists, import that one with the ID `" + omni.EtcdBackupS3ConfID + "`",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Always `" + omni.EtcdBackupS3ConfID + "`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket storing the backups",
+				Required:            true,
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Region of the bucket",
+				Optional:            true,
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "Endpoint of S3 compatible stores, e.g. `http://minio.example.com:9000`. AWS when not set",
+				Optional:            true,
+			},
+			"access_key_id": schema.StringAttribute{
+				MarkdownDescription: "Access key ID",
+				Optional:            true,
+			},
+			"secret_access_key": schema.StringAttribute{
+				MarkdownDescription: "Secret access key, changes made out of Terraform are not detected",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"session_token": schema.StringAttribute{
+				MarkdownDescription: "Session token of temporary credentials",
+				Optional:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniEtcdBackupS3ConfigurationResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The configuration is a singleton, creating it over an existing one would silently replace it.
+	existing, err := r.client.GetEtcdBackupS3Configuration()
+	if err != nil && !omniapi.IsNotFound(err) {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read etcd backup S3 configuration, got error: %s", err))
+		return
+	}
+	if err == nil && existing.Bucket != "" {
+		resp.Diagnostics.AddError("Resource Already Exists",
+			fmt.Sprintf("the etcd backup S3 configuration of Omni already exists (bucket %q), use terraform import with the ID %s to manage it", existing.Bucket, omni.EtcdBackupS3ConfID))
+		return
+	}
+
+	if err := r.client.ApplyEtcdBackupS3Configuration(etcdBackupS3ConfigurationFromModel(data)); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create etcd backup S3 configuration, got error: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(omni.EtcdBackupS3ConfID)
+
+	tflog.Trace(ctx, "create a resource omni_etcd_backup_s3_configuration")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniEtcdBackupS3ConfigurationResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conf, err := r.client.GetEtcdBackupS3Configuration()
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read etcd backup S3 configuration, got error: %s", err))
+		return
+	}
+
+	// The secret access key is kept as configured, Omni does not hand it back to every client.
+	data.ID = types.StringValue(omni.EtcdBackupS3ConfID)
+	data.Bucket = types.StringValue(conf.Bucket)
+	data.Region = optionalString(conf.Region)
+	data.Endpoint = optionalString(conf.Endpoint)
+	data.AccessKeyID = optionalString(conf.AccessKeyID)
+	data.SessionToken = optionalString(conf.SessionToken)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniEtcdBackupS3ConfigurationResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.ApplyEtcdBackupS3Configuration(etcdBackupS3ConfigurationFromModel(data)); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to update etcd backup S3 configuration, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniEtcdBackupS3ConfigurationResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteEtcdBackupS3Configuration(); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete etcd backup S3 configuration, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != omni.EtcdBackupS3ConfID {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("the etcd backup S3 configuration is imported with the ID %q", omni.EtcdBackupS3ConfID))
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniEtcdBackupS3ConfigurationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniEtcdBackupS3ConfigurationResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case !data.AccessKeyID.IsNull() && data.SecretAccessKey.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("secret_access_key"), "Missing Attribute Configuration",
+			"secret_access_key is required with access_key_id")
+	case data.AccessKeyID.IsNull() && !data.SecretAccessKey.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("access_key_id"), "Missing Attribute Configuration",
+			"access_key_id is required with secret_access_key")
+	}
+
+	if !data.SessionToken.IsNull() && data.AccessKeyID.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("session_token"), "Invalid Attribute Combination",
+			"session_token needs access_key_id and secret_access_key")
+	}
+}
+
+func etcdBackupS3ConfigurationFromModel(data OmniEtcdBackupS3ConfigurationResourceModel) omniapi.EtcdBackupS3Configuration {
+	return omniapi.EtcdBackupS3Configuration{
+		Bucket:          data.Bucket.ValueString(),
+		Region:          data.Region.ValueString(),
+		Endpoint:        data.Endpoint.ValueString(),
+		AccessKeyID:     data.AccessKeyID.ValueString(),
+		SecretAccessKey: data.SecretAccessKey.ValueString(),
+		SessionToken:    data.SessionToken.ValueString(),
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// newTestS3Server starts a minimal S3 compatible stand-in, enough for Omni to accept the configuration:
+// buckets exist and are empty, objects are accepted and dropped.
+func newTestS3Server(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+		switch {
+		case r.Method == http.MethodPut:
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case key != "":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/xml")
+			_ = xml.NewEncoder(w).Encode(struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Name     string
+				KeyCount int
+			}{Name: bucket})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// testAccCheckEtcdBackupStoreStatus checks that Omni switched its etcd backup store to S3 without error.
+func testAccCheckEtcdBackupStoreStatus(*terraform.State) error {
+	client := omniapi.NewClient(os.Getenv("OMNI_ENDPOINT"), os.Getenv("OMNI_SERVICE_ACCOUNT_KEY"))
+	if err := client.Open(); err != nil {
+		return err
+	}
+
+	status, err := client.GetEtcdBackupStoreStatus()
+	if err != nil {
+		return err
+	}
+
+	spec := status.TypedSpec().Value
+	if spec.ConfigurationName != "s3" || spec.ConfigurationError != "" {
+		return fmt.Errorf("expected the s3 etcd backup store, got %q (error %q)", spec.ConfigurationName, spec.ConfigurationError)
+	}
+
+	return nil
+}
+
+func TestAccOmniEtcdBackupS3ConfigurationResource(t *testing.T) {
+	endpoint := newTestS3Server(t).URL
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config: `
+resource "omni_etcd_backup_s3_configuration" "test" {
+  bucket        = "etcd-backups"
+  access_key_id = "minioadmin"
+}
+`,
+				ExpectError: regexp.MustCompile("secret_access_key is required"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniEtcdBackupS3ConfigurationResourceConfig("etcd-backups", endpoint),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_etcd_backup_s3_configuration.test", "id", "etcd-backup-s3-conf"),
+					resource.TestCheckResourceAttr("omni_etcd_backup_s3_configuration.test", "endpoint", endpoint),
+					testAccCheckEtcdBackupStoreStatus,
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "omni_etcd_backup_s3_configuration.test",
+				ImportState:             true,
+				ImportStateId:           "etcd-backup-s3-conf",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"secret_access_key"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccOmniEtcdBackupS3ConfigurationResourceConfig("etcd-backups-2", endpoint),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_etcd_backup_s3_configuration.test", "bucket", "etcd-backups-2"),
+					testAccCheckEtcdBackupStoreStatus,
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniEtcdBackupS3ConfigurationResourceConfig(bucket, endpoint string) string {
+	return fmt.Sprintf(`
+resource "omni_etcd_backup_s3_configuration" "test" {
+  bucket            = %q
+  region            = "us-east-1"
+  endpoint          = %q
+  access_key_id     = "minioadmin"
+  secret_access_key = "minioadmin"
+}
+`, bucket, endpoint)
+}
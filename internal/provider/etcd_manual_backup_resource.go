@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+const defaultEtcdManualBackupTimeout = 10 * time.Minute
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniEtcdManualBackupResource{}
+var _ resource.ResourceWithValidateConfig = &OmniEtcdManualBackupResource{}
+
+func NewOmniEtcdManualBackupResource() resource.Resource {
+	return &OmniEtcdManualBackupResource{}
+}
+
+// OmniEtcdManualBackupResource defines the resource implementation.
+type OmniEtcdManualBackupResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniEtcdManualBackupResourceModel describes the resource data model.
+type OmniEtcdManualBackupResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Cluster   types.String `tfsdk:"cluster"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	Timeout   types.String `tfsdk:"timeout"`
+	Snapshot  types.String `tfsdk:"snapshot"`
+	Size      types.Int64  `tfsdk:"size"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func (r *OmniEtcdManualBackupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_etcd_manual_backup"
+}
+
+func (r *OmniEtcdManualBackupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keep := []planmodifier.String{stringplanmodifier.UseStateForUnknown()}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_etcd_manual_backup resource. Takes an etcd backup of a cluster on creation, destroying the resource " +
+			"leaves the backup in the store until its retention expires",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Backup ID",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "Name of the cluster to back up, its etcd backups must be enabled in Omni",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Arbitrary values, a new backup is taken when they change (e.g. the target Talos version)",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration to wait for the backup (default `10m`)",
+				Optional:            true,
+			},
+			"snapshot": schema.StringAttribute{
+				MarkdownDescription: "Snapshot file name in the backup store",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"size": schema.Int64Attribute{
+				MarkdownDescription: "Backup size in bytes",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Backup timestamp (RFC 3339)",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+		},
+	}
+}
+
+func (r *OmniEtcdManualBackupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniEtcdManualBackupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniEtcdManualBackupResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := defaultEtcdManualBackupTimeout
+	if !data.Timeout.IsNull() {
+		var err error
+		if timeout, err = time.ParseDuration(data.Timeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("timeout"), "Invalid Attribute Value", err.Error())
+			return
+		}
+	}
+
+	backup, err := r.client.CreateEtcdManualBackup(data.Cluster.ValueString(), timeout)
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to back up etcd, got error: %s", err))
+		return
+	}
+
+	setEtcdBackup(&data, backup)
+
+	tflog.Trace(ctx, "create a resource omni_etcd_manual_backup")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniEtcdManualBackupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniEtcdManualBackupResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A backup removed by the retention policy is not taken again, the resource keeps describing it.
+	backup, err := r.client.GetEtcdBackup(data.Cluster.ValueString(), data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		tflog.Debug(ctx, "etcd backup is no longer in the backup store", map[string]any{"id": data.ID.ValueString()})
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read etcd backup, got error: %s", err))
+		return
+	}
+
+	setEtcdBackup(&data, backup)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniEtcdManualBackupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniEtcdManualBackupResourceModel
+
+	// Read Terraform plan data into the model, only timeout can change in place
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniEtcdManualBackupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Backups are only removed by the retention policy of the backup store.
+}
+
+func (r *OmniEtcdManualBackupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniEtcdManualBackupResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Timeout.IsNull() && !data.Timeout.IsUnknown() {
+		if timeout, err := time.ParseDuration(data.Timeout.ValueString()); err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("timeout"), "Invalid Attribute Value",
+				fmt.Sprintf("timeout must be a positive duration like 10m, got %q", data.Timeout.ValueString()))
+		}
+	}
+}
+
+func setEtcdBackup(data *OmniEtcdManualBackupResourceModel, backup *omniapi.EtcdBackup) {
+	data.ID = types.StringValue(backup.ID)
+	data.Snapshot = types.StringValue(backup.Snapshot)
+	data.Size = types.Int64Value(int64(backup.Size))
+	data.CreatedAt = types.StringValue(backup.CreatedAt.Format(time.RFC3339))
+}
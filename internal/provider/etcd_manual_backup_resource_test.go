@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOmniEtcdManualBackupResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccOmniEtcdManualBackupResourceConfig("v1.9.5"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("omni_etcd_manual_backup.test", "id"),
+					resource.TestCheckResourceAttrSet("omni_etcd_manual_backup.test", "snapshot"),
+					resource.TestCheckResourceAttrSet("omni_etcd_manual_backup.test", "size"),
+					resource.TestCheckResourceAttrSet("omni_etcd_manual_backup.test", "created_at"),
+				),
+			},
+			// A trigger change takes a new backup
+			{
+				Config: testAccOmniEtcdManualBackupResourceConfig("v1.10.0"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_etcd_manual_backup.test", "triggers.talos_version", "v1.10.0"),
+					resource.TestCheckResourceAttrSet("omni_etcd_manual_backup.test", "id"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniEtcdManualBackupResourceConfig(talosVersion string) string {
+	return fmt.Sprintf(`
+resource "omni_etcd_manual_backup" "test" {
+  cluster = "talos-default"
+  triggers = {
+    talos_version = %q
+  }
+}
+`, talosVersion)
+}
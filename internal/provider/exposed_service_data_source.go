@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &OmniExposedServiceDataSource{}
+
+func NewOmniExposedServiceDataSource() datasource.DataSource {
+	return &OmniExposedServiceDataSource{}
+}
+
+// OmniExposedServiceDataSource defines the data source implementation.
+type OmniExposedServiceDataSource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniExposedServiceDataSourceModel describes the data source data model.
+type OmniExposedServiceDataSourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Cluster  types.String `tfsdk:"cluster"`
+	Label    types.String `tfsdk:"label"`
+	Services types.List   `tfsdk:"services"`
+	URLs     types.Map    `tfsdk:"urls"`
+}
+
+var exposedServiceAttrTypes = map[string]attr.Type{
+	"id":    types.StringType,
+	"name":  types.StringType,
+	"label": types.StringType,
+	"icon":  types.StringType,
+	"port":  types.Int64Type,
+	"url":   types.StringType,
+	"error": types.StringType,
+}
+
+func (d *OmniExposedServiceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_exposed_service"
+}
+
+func (d *OmniExposedServiceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_exposed_service data source. Lists the Kubernetes services of a cluster exposed by the Omni workload proxy, " +
+			"services are exposed through their `omni-kube-service-exposer.sidero.dev/port` annotation",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Cluster name",
+				Computed:            true,
+			},
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "Name of the cluster, its workload proxy must be enabled",
+				Required:            true,
+			},
+			"label": schema.StringAttribute{
+				MarkdownDescription: "Only list the services with this label",
+				Optional:            true,
+			},
+			"services": schema.ListNestedAttribute{
+				MarkdownDescription: "Exposed services, sorted by name",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Exposed service ID",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Kubernetes service as `name.namespace`",
+							Computed:            true,
+						},
+						"label": schema.StringAttribute{
+							MarkdownDescription: "Label displayed in Omni",
+							Computed:            true,
+						},
+						"icon": schema.StringAttribute{
+							MarkdownDescription: "Icon displayed in Omni, base64 encoded",
+							Computed:            true,
+						},
+						"port": schema.Int64Attribute{
+							MarkdownDescription: "Host port the service is exposed on",
+							Computed:            true,
+						},
+						"url": schema.StringAttribute{
+							MarkdownDescription: "URL of the service",
+							Computed:            true,
+						},
+						"error": schema.StringAttribute{
+							MarkdownDescription: "Last error exposing the service",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"urls": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "URLs of the exposed services by name",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *OmniExposedServiceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *OmniExposedServiceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OmniExposedServiceDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	services, err := d.client.ListExposedServices(data.Cluster.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to list exposed services, got error: %s", err))
+		return
+	}
+
+	if !data.Label.IsNull() {
+		services = filterExposedServices(services, data.Label.ValueString())
+	}
+
+	slices.SortFunc(services, func(a, b *omniapi.ExposedService) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	items := make([]attr.Value, 0, len(services))
+	urls := make(map[string]attr.Value, len(services))
+	for _, s := range services {
+		items = append(items, types.ObjectValueMust(exposedServiceAttrTypes, map[string]attr.Value{
+			"id":    types.StringValue(s.ID),
+			"name":  types.StringValue(s.Name),
+			"label": types.StringValue(s.Label),
+			"icon":  types.StringValue(s.IconBase64),
+			"port":  types.Int64Value(int64(s.Port)),
+			"url":   types.StringValue(s.URL),
+			"error": types.StringValue(s.Error),
+		}))
+		urls[s.Name] = types.StringValue(s.URL)
+	}
+
+	data.ID = data.Cluster
+	data.Services = types.ListValueMust(types.ObjectType{AttrTypes: exposedServiceAttrTypes}, items)
+	data.URLs = types.MapValueMust(types.StringType, urls)
+
+	tflog.Trace(ctx, "read a data source omni_exposed_service")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// filterExposedServices returns the services with the label.
+func filterExposedServices(services []*omniapi.ExposedService, label string) []*omniapi.ExposedService {
+	return slices.DeleteFunc(slices.Clone(services), func(s *omniapi.ExposedService) bool {
+		return s.Label != label
+	})
+}
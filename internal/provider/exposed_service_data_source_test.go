@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+func TestAccOmniExposedServiceDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccOmniExposedServiceDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.omni_exposed_service.test", "id", "talos-default"),
+					resource.TestCheckResourceAttrSet("data.omni_exposed_service.test", "services.#"),
+				),
+			},
+		},
+	})
+}
+
+const testAccOmniExposedServiceDataSourceConfig = `
+data "omni_exposed_service" "test" {
+  cluster = "talos-default"
+  label   = "Grafana"
+}
+`
+
+func TestFilterExposedServices(t *testing.T) {
+	services := []*omniapi.ExposedService{
+		{Name: "grafana.monitoring", Label: "Grafana"},
+		{Name: "argocd-server.argocd", Label: "Argo CD"},
+		{Name: "grafana.staging", Label: "Grafana"},
+	}
+
+	filtered := filterExposedServices(services, "Grafana")
+	if len(filtered) != 2 || filtered[0].Name != "grafana.monitoring" || filtered[1].Name != "grafana.staging" {
+		t.Errorf("unexpected filtered services %v", filtered)
+	}
+
+	if len(services) != 3 || services[1].Name != "argocd-server.argocd" {
+		t.Errorf("filtering changed the listed services %v", services)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// infraProviderHeartbeatTimeout is the age of the last heartbeat past which a provider is reported unhealthy.
+const infraProviderHeartbeatTimeout = 2 * time.Minute
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniInfraProviderResource{}
+var _ resource.ResourceWithImportState = &OmniInfraProviderResource{}
+var _ resource.ResourceWithValidateConfig = &OmniInfraProviderResource{}
+
+func NewOmniInfraProviderResource() resource.Resource {
+	return &OmniInfraProviderResource{}
+}
+
+// OmniInfraProviderResource defines the resource implementation.
+type OmniInfraProviderResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniInfraProviderResourceModel describes the resource data model.
+type OmniInfraProviderResourceModel struct {
+	ID                  types.String `tfsdk:"id"`
+	ProviderID          types.String `tfsdk:"provider_id"`
+	TTL                 types.String `tfsdk:"ttl"`
+	ServiceAccount      types.String `tfsdk:"service_account"`
+	Key                 types.String `tfsdk:"key"`
+	Connected           types.Bool   `tfsdk:"connected"`
+	Healthy             types.Bool   `tfsdk:"healthy"`
+	LastHeartbeat       types.String `tfsdk:"last_heartbeat"`
+	HealthError         types.String `tfsdk:"health_error"`
+	ProviderName        types.String `tfsdk:"provider_name"`
+	ProviderDescription types.String `tfsdk:"provider_description"`
+}
+
+func (r *OmniInfraProviderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_infra_provider"
+}
+
+func (r *OmniInfraProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keep := []planmodifier.String{stringplanmodifier.UseStateForUnknown()}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_infra_provider resource. Registers an infra provider ID with its service account, " +
+			"the status attributes are refreshed out of what the provider reports once running",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Infra provider ID",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"provider_id": schema.StringAttribute{
+				MarkdownDescription: "Infra provider ID, the `--id` of the provider",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.StringAttribute{
+				MarkdownDescription: "Lifetime of the service account key (default `8760h`). Changing it recreates the provider to issue a new key",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service_account": schema.StringAttribute{
+				MarkdownDescription: "Name of the service account of the provider",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "Service account key of the provider, to be used as `OMNI_SERVICE_ACCOUNT_KEY`. Not available after an import",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers:       keep,
+			},
+			"connected": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider has connected to Omni",
+				Computed:            true,
+			},
+			"healthy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider reports no error and sent a heartbeat in the last 2 minutes",
+				Computed:            true,
+			},
+			"last_heartbeat": schema.StringAttribute{
+				MarkdownDescription: "Time of the last heartbeat of the provider (RFC 3339)",
+				Computed:            true,
+			},
+			"health_error": schema.StringAttribute{
+				MarkdownDescription: "Error reported by the provider health check",
+				Computed:            true,
+			},
+			"provider_name": schema.StringAttribute{
+				MarkdownDescription: "Name reported by the provider",
+				Computed:            true,
+			},
+			"provider_description": schema.StringAttribute{
+				MarkdownDescription: "Description reported by the provider",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *OmniInfraProviderResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniInfraProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniInfraProviderResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ttl := defaultServiceAccountTTL
+	if !data.TTL.IsNull() {
+		var err error
+		if ttl, err = time.ParseDuration(data.TTL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid Attribute Value", err.Error())
+			return
+		}
+	}
+
+	key, err := r.client.CreateInfraProviderServiceAccount(data.ProviderID.ValueString(), ttl)
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create infra provider service account, got error: %s", err))
+		return
+	}
+
+	data.ID = data.ProviderID
+	data.ServiceAccount = types.StringValue(omniapi.InfraProviderServiceAccountName(data.ProviderID.ValueString()))
+	data.Key = types.StringValue(key.Key)
+
+	if err := r.readStatus(&data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read infra provider status, got error: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "create a resource omni_infra_provider")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniInfraProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniInfraProviderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := omniapi.InfraProviderServiceAccountName(data.ID.ValueString())
+
+	_, err := r.client.GetServiceAccount(name)
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read infra provider service account, got error: %s", err))
+		return
+	}
+
+	data.ProviderID = data.ID
+	data.ServiceAccount = types.StringValue(name)
+
+	if err := r.readStatus(&data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read infra provider status, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniInfraProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniInfraProviderResourceModel
+
+	// Read Terraform plan data into the model, every configurable attribute requires a replacement
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.readStatus(&data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read infra provider status, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniInfraProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniInfraProviderResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DestroyServiceAccount(omniapi.InfraProviderServiceAccountName(data.ID.ValueString())); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete infra provider service account, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniInfraProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniInfraProviderResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniInfraProviderResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.TTL.IsNull() && !data.TTL.IsUnknown() {
+		if ttl, err := time.ParseDuration(data.TTL.ValueString()); err != nil || ttl <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid Attribute Value",
+				fmt.Sprintf("ttl must be a positive duration like 8760h, got %q", data.TTL.ValueString()))
+		}
+	}
+}
+
+func (r *OmniInfraProviderResource) readStatus(data *OmniInfraProviderResourceModel) error {
+	status, err := r.client.GetInfraProviderStatus(data.ID.ValueString())
+	if err != nil {
+		return err
+	}
+
+	data.Connected = types.BoolValue(status.Connected)
+	data.Healthy = types.BoolValue(infraProviderHealthy(status, time.Now()))
+	data.HealthError = types.StringValue(status.Error)
+	data.ProviderName = types.StringValue(status.Name)
+	data.ProviderDescription = types.StringValue(status.Description)
+
+	data.LastHeartbeat = types.StringNull()
+	if !status.LastHeartbeat.IsZero() {
+		data.LastHeartbeat = types.StringValue(status.LastHeartbeat.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func infraProviderHealthy(status *omniapi.InfraProviderStatus, now time.Time) bool {
+	return status.Connected && status.Error == "" && now.Sub(status.LastHeartbeat) < infraProviderHeartbeatTimeout
+}
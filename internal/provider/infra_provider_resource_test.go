@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+func TestAccOmniInfraProviderResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccOmniInfraProviderResourceConfig("tf-test", "1y"),
+				ExpectError: regexp.MustCompile("ttl must be a positive duration"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniInfraProviderResourceConfig("tf-test", "24h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_infra_provider.test", "id", "tf-test"),
+					resource.TestCheckResourceAttr("omni_infra_provider.test", "service_account", "infra-provider:tf-test"),
+					resource.TestCheckResourceAttrSet("omni_infra_provider.test", "key"),
+					resource.TestCheckResourceAttr("omni_infra_provider.test", "connected", "false"),
+					resource.TestCheckResourceAttr("omni_infra_provider.test", "healthy", "false"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "omni_infra_provider.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"key", "ttl"},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniInfraProviderResourceConfig(id, ttl string) string {
+	return fmt.Sprintf(`
+resource "omni_infra_provider" "test" {
+  provider_id = %q
+  ttl         = %q
+}
+`, id, ttl)
+}
+
+func TestInfraProviderHealthy(t *testing.T) {
+	now := time.Now()
+
+	for _, tt := range []struct {
+		status   omniapi.InfraProviderStatus
+		expected bool
+	}{
+		{omniapi.InfraProviderStatus{Connected: true, LastHeartbeat: now.Add(-30 * time.Second)}, true},
+		{omniapi.InfraProviderStatus{Connected: true, LastHeartbeat: now.Add(-5 * time.Minute)}, false},
+		{omniapi.InfraProviderStatus{Connected: true, LastHeartbeat: now, Error: "ipmi unreachable"}, false},
+		{omniapi.InfraProviderStatus{Connected: false}, false},
+	} {
+		if got := infraProviderHealthy(&tt.status, now); got != tt.expected {
+			t.Errorf("infraProviderHealthy(%+v) = %v, expected %v", tt.status, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+const defaultInstallationMediaArchitecture = "amd64"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &OmniInstallationMediaDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &OmniInstallationMediaDataSource{}
+
+func NewOmniInstallationMediaDataSource() datasource.DataSource {
+	return &OmniInstallationMediaDataSource{}
+}
+
+// OmniInstallationMediaDataSource defines the data source implementation.
+type OmniInstallationMediaDataSource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniInstallationMediaDataSourceModel describes the data source data model.
+type OmniInstallationMediaDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	TalosVersion types.String `tfsdk:"talos_version"`
+	Platform     types.String `tfsdk:"platform"`
+	Architecture types.String `tfsdk:"architecture"`
+	SchematicID  types.String `tfsdk:"schematic_id"`
+	KernelArgs   types.List   `tfsdk:"kernel_args"`
+	SecureBoot   types.Bool   `tfsdk:"secure_boot"`
+	Name         types.String `tfsdk:"name"`
+	Filename     types.String `tfsdk:"filename"`
+	URL          types.String `tfsdk:"url"`
+	PXEURL       types.String `tfsdk:"pxe_url"`
+	Media        types.List   `tfsdk:"media"`
+}
+
+var installationMediaAttrTypes = map[string]attr.Type{
+	"id":           types.StringType,
+	"name":         types.StringType,
+	"architecture": types.StringType,
+	"profile":      types.StringType,
+}
+
+func (d *OmniInstallationMediaDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_installation_media"
+}
+
+func (d *OmniInstallationMediaDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_installation_media data source. Computes the URL of a boot media joining Omni through SideroLink",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Installation media ID",
+				Computed:            true,
+			},
+			"talos_version": schema.StringAttribute{
+				MarkdownDescription: "Talos version of the media, e.g. `v1.10.0`",
+				Required:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Name or profile of the media, e.g. `iso`, `metal`, `aws`, `nocloud`",
+				Required:            true,
+			},
+			"architecture": schema.StringAttribute{
+				MarkdownDescription: "Architecture of the media, `amd64` (default) or `arm64`",
+				Optional:            true,
+			},
+			"schematic_id": schema.StringAttribute{
+				MarkdownDescription: "Schematic of the media, e.g. `omni_schematic.x.id`. A schematic with the SideroLink parameters " +
+					"and `kernel_args` is created when not set",
+				Optional: true,
+			},
+			"kernel_args": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Extra kernel arguments, conflicts with `schematic_id`",
+				Optional:            true,
+			},
+			"secure_boot": schema.BoolAttribute{
+				MarkdownDescription: "Secure boot media",
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the media",
+				Computed:            true,
+			},
+			"filename": schema.StringAttribute{
+				MarkdownDescription: "File name of the media",
+				Computed:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Omni URL generating the media, downloads are authenticated like any Omni request",
+				Computed:            true,
+			},
+			"pxe_url": schema.StringAttribute{
+				MarkdownDescription: "PXE boot URL, set when the schematic is created by the data source",
+				Computed:            true,
+			},
+			"media": schema.ListNestedAttribute{
+				MarkdownDescription: "Every installation media available in Omni",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Installation media ID",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the media",
+							Computed:            true,
+						},
+						"architecture": schema.StringAttribute{
+							MarkdownDescription: "Architecture of the media",
+							Computed:            true,
+						},
+						"profile": schema.StringAttribute{
+							MarkdownDescription: "Image Factory profile of the media",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *OmniInstallationMediaDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *OmniInstallationMediaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OmniInstallationMediaDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	media, err := d.client.ListInstallationMedia()
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to list installation media, got error: %s", err))
+		return
+	}
+
+	arch := defaultInstallationMediaArchitecture
+	if !data.Architecture.IsNull() {
+		arch = data.Architecture.ValueString()
+	}
+
+	found, err := omniapi.FindInstallationMedia(media, data.Platform.ValueString(), arch)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("platform"), "Unknown Installation Media", err.Error())
+		return
+	}
+
+	secureBoot := data.SecureBoot.ValueBool()
+	if secureBoot && !found.SecureBoot {
+		resp.Diagnostics.AddAttributeError(path.Root("secure_boot"), "Invalid Attribute Value",
+			fmt.Sprintf("installation media %s does not support secure boot", found.ID))
+		return
+	}
+
+	schematicID := data.SchematicID.ValueString()
+	data.PXEURL = types.StringNull()
+
+	if data.SchematicID.IsNull() {
+		schematic := omniapi.Schematic{
+			TalosVersion: data.TalosVersion.ValueString(),
+			SecureBoot:   secureBoot,
+			MediaID:      found.ID,
+		}
+
+		if !data.KernelArgs.IsNull() {
+			resp.Diagnostics.Append(data.KernelArgs.ElementsAs(ctx, &schematic.KernelArgs, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		images, err := d.client.CreateSchematic(schematic)
+		if err != nil {
+			resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create schematic, got error: %s", err))
+			return
+		}
+
+		schematicID = images.ID
+		data.PXEURL = types.StringValue(images.PXEURL)
+	}
+
+	url, err := d.client.InstallationMediaURL(schematicID, data.TalosVersion.ValueString(), found.ID, secureBoot)
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to build installation media URL, got error: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(found.ID)
+	data.Name = types.StringValue(found.Name)
+	data.Filename = types.StringValue(found.Filename)
+	data.URL = types.StringValue(url)
+
+	items := make([]attr.Value, 0, len(media))
+	for _, m := range media {
+		items = append(items, types.ObjectValueMust(installationMediaAttrTypes, map[string]attr.Value{
+			"id":           types.StringValue(m.ID),
+			"name":         types.StringValue(m.Name),
+			"architecture": types.StringValue(m.Architecture),
+			"profile":      types.StringValue(m.Profile),
+		}))
+	}
+	data.Media = types.ListValueMust(types.ObjectType{AttrTypes: installationMediaAttrTypes}, items)
+
+	tflog.Trace(ctx, "read a data source omni_installation_media")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *OmniInstallationMediaDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data OmniInstallationMediaDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.SchematicID.IsNull() && !data.KernelArgs.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("kernel_args"), "Invalid Attribute Combination",
+			"kernel_args cannot be combined with schematic_id, set them in the schematic")
+	}
+
+	if !data.Architecture.IsNull() && !data.Architecture.IsUnknown() {
+		if arch := data.Architecture.ValueString(); arch != "amd64" && arch != "arm64" {
+			resp.Diagnostics.AddAttributeError(path.Root("architecture"), "Invalid Attribute Value",
+				fmt.Sprintf("architecture must be amd64 or arm64, got %q", arch))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+func TestAccOmniInstallationMediaDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccOmniInstallationMediaDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.omni_installation_media.test", "id", "iso-amd64"),
+					resource.TestMatchResourceAttr("data.omni_installation_media.test", "url", regexp.MustCompile(`/image/[0-9a-f]+/v1\.10\.0/iso-amd64$`)),
+					resource.TestCheckResourceAttrSet("data.omni_installation_media.test", "pxe_url"),
+					resource.TestCheckResourceAttrSet("data.omni_installation_media.test", "media.#"),
+				),
+			},
+		},
+	})
+}
+
+const testAccOmniInstallationMediaDataSourceConfig = `
+data "omni_installation_media" "test" {
+  talos_version = "v1.10.0"
+  platform      = "iso"
+  kernel_args   = ["net.ifnames=0"]
+}
+`
+
+func TestFindInstallationMedia(t *testing.T) {
+	media := []omniapi.InstallationMedia{
+		{ID: "iso-amd64", Name: "ISO (amd64)", Architecture: "amd64", Profile: "iso"},
+		{ID: "iso-arm64", Name: "ISO (arm64)", Architecture: "arm64", Profile: "iso"},
+		{ID: "metal-amd64", Name: "Bare Metal (amd64)", Architecture: "amd64", Profile: "metal"},
+		{ID: "aws-amd64", Name: "AWS (amd64)", Architecture: "amd64", Profile: "aws"},
+		{ID: "aws-amd64-2", Name: "AWS 2 (amd64)", Architecture: "amd64", Profile: "aws"},
+	}
+
+	for _, tc := range []struct {
+		platform, arch, expected string
+	}{
+		{"iso", "arm64", "iso-arm64"},
+		{"metal", "amd64", "metal-amd64"},
+		{"Bare Metal (amd64)", "amd64", "metal-amd64"},
+		{"AWS 2 (amd64)", "amd64", "aws-amd64-2"},
+	} {
+		found, err := omniapi.FindInstallationMedia(media, tc.platform, tc.arch)
+		if err != nil {
+			t.Errorf("%s/%s: %s", tc.platform, tc.arch, err)
+		} else if found.ID != tc.expected {
+			t.Errorf("%s/%s: expected %s, got %s", tc.platform, tc.arch, tc.expected, found.ID)
+		}
+	}
+
+	if _, err := omniapi.FindInstallationMedia(media, "aws", "amd64"); err == nil {
+		t.Error("expected an error for an ambiguous platform")
+	}
+
+	if _, err := omniapi.FindInstallationMedia(media, "metal", "arm64"); err == nil {
+		t.Error("expected an error for a missing architecture")
+	}
+}
@@ -44,6 +44,8 @@ func (r *OmniKubeconfigResource) Metadata(ctx context.Context, req resource.Meta
 
 func (r *OmniKubeconfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: omniKubeconfigSchemaVersion,
+
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "omni_kubeconfig resource",
 
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithUpgradeState = &OmniKubeconfigResource{}
+
+// omniKubeconfigSchemaVersion is the current omni_kubeconfig schema version.
+// Bump it and add an upgrader below whenever an attribute is renamed, removed or changes type.
+const omniKubeconfigSchemaVersion = 1
+
+// OmniKubeconfigResourceModelV0 describes the omni_kubeconfig data model of schema version 0.
+type OmniKubeconfigResourceModelV0 struct {
+	Kubeconfig  types.String `tfsdk:"kubeconfig"`
+	User        types.String `tfsdk:"user"`
+	Groups      types.List   `tfsdk:"groups"`
+	ClusterName types.String `tfsdk:"cluster_name"`
+	ID          types.String `tfsdk:"id"`
+}
+
+func (r *OmniKubeconfigResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"cluster_name": schema.StringAttribute{Required: true},
+					"user":         schema.StringAttribute{Optional: true},
+					"groups":       schema.ListAttribute{ElementType: types.StringType, Optional: true},
+					"kubeconfig":   schema.StringAttribute{Computed: true},
+					"id":           schema.StringAttribute{Computed: true},
+				},
+			},
+			StateUpgrader: upgradeOmniKubeconfigStateV0,
+		},
+	}
+}
+
+// upgradeOmniKubeconfigStateV0 maps version 0 to version 1, which share the same layout.
+func upgradeOmniKubeconfigStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior OmniKubeconfigResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data := OmniKubeconfigResourceModel(prior)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
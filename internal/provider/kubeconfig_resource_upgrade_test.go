@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOmniKubeconfigResourceUpgradeStateV0(t *testing.T) {
+	state := testUpgradeState(t, NewOmniKubeconfigResource(), 0, "testdata/omni_kubeconfig_state_v0.json")
+
+	var data OmniKubeconfigResourceModel
+	if diags := state.Get(context.Background(), &data); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if data.ClusterName.ValueString() != "omni-cluster-1" || data.ID.ValueString() != "omni-cluster-1" {
+		t.Errorf("expected cluster omni-cluster-1, got %q / %q", data.ClusterName.ValueString(), data.ID.ValueString())
+	}
+	if data.User.ValueString() != "admin" {
+		t.Errorf("expected user admin, got %q", data.User.ValueString())
+	}
+	if len(data.Groups.Elements()) != 1 {
+		t.Errorf("expected 1 group, got %d", len(data.Groups.Elements()))
+	}
+	if data.Kubeconfig.IsNull() {
+		t.Error("expected kubeconfig to be kept")
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniMachineClassResource{}
+var _ resource.ResourceWithImportState = &OmniMachineClassResource{}
+var _ resource.ResourceWithValidateConfig = &OmniMachineClassResource{}
+var _ resource.ResourceWithModifyPlan = &OmniMachineClassResource{}
+
+func NewOmniMachineClassResource() resource.Resource {
+	return &OmniMachineClassResource{}
+}
+
+// OmniMachineClassResource defines the resource implementation.
+type OmniMachineClassResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniMachineClassResourceModel describes the resource data model.
+type OmniMachineClassResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	MatchLabels      types.List   `tfsdk:"match_labels"`
+	MatchingMachines types.List   `tfsdk:"matching_machines"`
+}
+
+func (r *OmniMachineClassResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_class"
+}
+
+func (r *OmniMachineClassResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_machine_class resource",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Machine class ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the machine class",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"match_labels": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Label selectors matching the machines of the class. Each selector is a comma separated list of " +
+					"expressions (`key`, `!key`, `key = value`, `key != value`, `key in (a, b)`, `key > 1`, ...) which must all match; " +
+					"a machine belongs to the class when any selector matches",
+				Required: true,
+			},
+			"matching_machines": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "UUIDs of the machines matching `match_labels`, refreshed on read and whenever `match_labels` changes",
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *OmniMachineClassResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniMachineClassResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniMachineClassResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var matchLabels []string
+	resp.Diagnostics.Append(data.MatchLabels.ElementsAs(ctx, &matchLabels, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Creating over an existing machine class would silently replace its match labels.
+	_, err := r.client.GetMachineClass(data.Name.ValueString())
+	if err != nil && !omniapi.IsNotFound(err) {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read machine class, got error: %s", err))
+		return
+	}
+	if err == nil {
+		resp.Diagnostics.AddError("Resource Already Exists",
+			fmt.Sprintf("machine class %s already exists, use terraform import with the ID %s to manage it", data.Name.ValueString(), data.Name.ValueString()))
+		return
+	}
+
+	if err := r.client.ApplyMachineClass(data.Name.ValueString(), matchLabels); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create machine class, got error: %s", err))
+		return
+	}
+
+	data.ID = data.Name
+	if data.MatchingMachines.IsUnknown() {
+		resp.Diagnostics.Append(r.readMatchingMachines(matchLabels, &data)...)
+	}
+
+	tflog.Trace(ctx, "create a resource omni_machine_class")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineClassResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniMachineClassResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	matchLabels, err := r.client.GetMachineClass(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read machine class, got error: %s", err))
+		return
+	}
+
+	data.Name = data.ID
+	data.MatchLabels = types.ListValueMust(types.StringType, stringValues(matchLabels))
+
+	resp.Diagnostics.Append(r.readMatchingMachines(matchLabels, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineClassResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniMachineClassResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var matchLabels []string
+	resp.Diagnostics.Append(data.MatchLabels.ElementsAs(ctx, &matchLabels, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.ApplyMachineClass(data.Name.ValueString(), matchLabels); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to update machine class, got error: %s", err))
+		return
+	}
+
+	if data.MatchingMachines.IsUnknown() {
+		resp.Diagnostics.Append(r.readMatchingMachines(matchLabels, &data)...)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineClassResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniMachineClassResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteMachineClass(data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete machine class, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniMachineClassResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniMachineClassResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniMachineClassResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.MatchLabels.IsUnknown() {
+		return
+	}
+
+	if len(data.MatchLabels.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("match_labels"), "Invalid Attribute Value", "match_labels must contain at least one selector")
+		return
+	}
+
+	for i, v := range data.MatchLabels.Elements() {
+		selector, ok := v.(types.String)
+		if !ok || selector.IsUnknown() || selector.IsNull() {
+			continue
+		}
+
+		if _, err := omniapi.ParseMachineClassSelectors([]string{selector.ValueString()}); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("match_labels").AtListIndex(i), "Invalid Attribute Value",
+				fmt.Sprintf("invalid label selector %q: %s", selector.ValueString(), err))
+		}
+	}
+}
+
+// ModifyPlan leaves matching_machines unknown when match_labels changes, it is carried over from the state otherwise.
+func (r *OmniMachineClassResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state OmniMachineClassResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.MatchLabels.Equal(state.MatchLabels) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("matching_machines"), types.ListUnknown(types.StringType))...)
+	}
+}
+
+func (r *OmniMachineClassResource) readMatchingMachines(matchLabels []string, data *OmniMachineClassResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	machines, err := r.client.GetMatchingMachines(matchLabels)
+	if err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to list matching machines, got error: %s", err))
+		return diags
+	}
+
+	data.MatchingMachines = types.ListValueMust(types.StringType, stringValues(machines))
+
+	return diags
+}
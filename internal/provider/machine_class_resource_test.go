@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOmniMachineClassResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccOmniMachineClassResourceConfig("profile in (gpu"),
+				ExpectError: regexp.MustCompile("invalid label selector"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniMachineClassResourceConfig("profile = gpu"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_class.test", "id", "gpu-workers"),
+					resource.TestCheckResourceAttrSet("omni_machine_class.test", "matching_machines.#"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "omni_machine_class.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccOmniMachineClassResourceConfig("profile in (gpu, storage), !omni.sidero.dev/cluster"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_class.test", "match_labels.0", "profile in (gpu, storage), !omni.sidero.dev/cluster"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniMachineClassResourceConfig(selector string) string {
+	return fmt.Sprintf(`
+resource "omni_machine_class" "test" {
+  name         = "gpu-workers"
+  match_labels = [%q]
+}
+`, selector)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniMachineLabelsResource{}
+var _ resource.ResourceWithImportState = &OmniMachineLabelsResource{}
+var _ resource.ResourceWithValidateConfig = &OmniMachineLabelsResource{}
+
+func NewOmniMachineLabelsResource() resource.Resource {
+	return &OmniMachineLabelsResource{}
+}
+
+// OmniMachineLabelsResource defines the resource implementation.
+type OmniMachineLabelsResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniMachineLabelsResourceModel describes the resource data model.
+type OmniMachineLabelsResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Machine types.String `tfsdk:"machine"`
+	Labels  types.Map    `tfsdk:"labels"`
+}
+
+func (r *OmniMachineLabelsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_labels"
+}
+
+func (r *OmniMachineLabelsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_machine_labels resource. Manages every user label of a machine, system labels are left untouched",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Machine UUID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"machine": schema.StringAttribute{
+				MarkdownDescription: "Machine UUID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"labels": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "User labels of the machine",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (r *OmniMachineLabelsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniMachineLabelsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniMachineLabelsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, ok := r.client.FindMachineByUuid(data.Machine.ValueString()); !ok {
+		resp.Diagnostics.AddAttributeError(path.Root("machine"), "Unknown Machine",
+			fmt.Sprintf("machine %s is not registered in Omni", data.Machine.ValueString()))
+		return
+	}
+
+	data.ID = data.Machine
+
+	resp.Diagnostics.Append(r.setLabels(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "create a resource omni_machine_labels")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineLabelsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniMachineLabelsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	labels, err := r.client.GetMachineLabels(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read machine labels, got error: %s", err))
+		return
+	}
+
+	var diags diag.Diagnostics
+	data.Machine = data.ID
+	data.Labels, diags = types.MapValueFrom(ctx, types.StringType, labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineLabelsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniMachineLabelsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setLabels(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineLabelsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniMachineLabelsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.SetMachineLabels(data.ID.ValueString(), nil); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to remove machine labels, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniMachineLabelsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniMachineLabelsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniMachineLabelsResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for key := range data.Labels.Elements() {
+		if strings.HasPrefix(key, omni.SystemLabelPrefix) {
+			resp.Diagnostics.AddAttributeError(path.Root("labels").AtMapKey(key), "Invalid Attribute Value",
+				fmt.Sprintf("label %q uses the %s prefix reserved to Omni", key, omni.SystemLabelPrefix))
+		}
+	}
+}
+
+func (r *OmniMachineLabelsResource) setLabels(ctx context.Context, data OmniMachineLabelsResourceModel) diag.Diagnostics {
+	labels := map[string]string{}
+
+	diags := data.Labels.ElementsAs(ctx, &labels, false)
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := r.client.SetMachineLabels(data.ID.ValueString(), labels); err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to set machine labels, got error: %s", err))
+	}
+
+	return diags
+}
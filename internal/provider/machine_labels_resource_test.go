@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOmniMachineLabelsResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config: `
+resource "omni_machine_labels" "test" {
+  machine = "e2b1c3d4-0000-0000-0000-000000000001"
+  labels = {
+    "omni.sidero.dev/cluster" = "test"
+  }
+}`,
+				ExpectError: regexp.MustCompile("reserved to Omni"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniMachineLabelsResourceConfig("gpu"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_labels.test", "labels.profile", "gpu"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "omni_machine_labels.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccOmniMachineLabelsResourceConfig("storage"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_labels.test", "labels.profile", "storage"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniMachineLabelsResourceConfig(profile string) string {
+	return fmt.Sprintf(`
+data "omni_machine" "test" {
+  hardware_address = "00:00:00:00:00:01"
+}
+
+resource "omni_machine_labels" "test" {
+  machine = data.omni_machine.test.uuid
+  labels = {
+    profile = %q
+    rack    = "r1"
+  }
+}
+`, profile)
+}
@@ -0,0 +1,364 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+const defaultMachineRequestSetTimeout = 30 * time.Minute
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniMachineRequestSetResource{}
+var _ resource.ResourceWithImportState = &OmniMachineRequestSetResource{}
+var _ resource.ResourceWithValidateConfig = &OmniMachineRequestSetResource{}
+
+func NewOmniMachineRequestSetResource() resource.Resource {
+	return &OmniMachineRequestSetResource{}
+}
+
+// OmniMachineRequestSetResource defines the resource implementation.
+type OmniMachineRequestSetResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniMachineRequestSetResourceModel describes the resource data model.
+type OmniMachineRequestSetResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	ProviderID   types.String `tfsdk:"provider_id"`
+	TalosVersion types.String `tfsdk:"talos_version"`
+	Extensions   types.List   `tfsdk:"extensions"`
+	KernelArgs   types.List   `tfsdk:"kernel_args"`
+	MetaValues   types.Map    `tfsdk:"meta_values"`
+	ProviderData types.String `tfsdk:"provider_data"`
+	MachineCount types.Int64  `tfsdk:"machine_count"`
+	Timeout      types.String `tfsdk:"timeout"`
+	Machines     types.List   `tfsdk:"machines"`
+}
+
+func (r *OmniMachineRequestSetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_request_set"
+}
+
+func (r *OmniMachineRequestSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_machine_request_set resource. Requests machines to an infra provider, the schematic of the machines " +
+			"is built out of `extensions`, `kernel_args` and `meta_values`. Scaling down lets the provider pick the machines to deprovision",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Machine request set ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the machine request set",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"provider_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the infra provider provisioning the machines",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"talos_version": schema.StringAttribute{
+				MarkdownDescription: "Talos version the machines are provisioned with, e.g. `v1.10.0`. Changes only apply to new machines",
+				Required:            true,
+			},
+			"extensions": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "System extensions, e.g. `siderolabs/iscsi-tools`",
+				Optional:            true,
+			},
+			"kernel_args": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Extra kernel arguments",
+				Optional:            true,
+			},
+			"meta_values": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Talos META values by key, keys are decimal or hexadecimal (`0x0a`) integers",
+				Optional:            true,
+			},
+			"provider_data": schema.StringAttribute{
+				MarkdownDescription: "Provider specific parameters in YAML, e.g. the instance size",
+				Optional:            true,
+			},
+			"machine_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of machines to provision",
+				Required:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration to wait for the machines to register on create and update (default `30m`)",
+				Optional:            true,
+			},
+			"machines": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "UUIDs of the provisioned machines, sorted, e.g. for the `machines` of a cluster template",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *OmniMachineRequestSetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniMachineRequestSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniMachineRequestSetResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.Name
+
+	resp.Diagnostics.Append(r.apply(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "create a resource omni_machine_request_set")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineRequestSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniMachineRequestSetResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	set, err := r.client.GetMachineRequestSet(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read machine request set, got error: %s", err))
+		return
+	}
+
+	data.Name = data.ID
+	data.ProviderID = types.StringValue(set.ProviderID)
+	data.TalosVersion = types.StringValue(set.TalosVersion)
+	data.MachineCount = types.Int64Value(int64(set.MachineCount))
+
+	// Unset lists stay null rather than drifting to empty lists.
+	if len(set.Extensions) > 0 || !data.Extensions.IsNull() {
+		data.Extensions = types.ListValueMust(types.StringType, stringValues(set.Extensions))
+	}
+	if len(set.KernelArgs) > 0 || !data.KernelArgs.IsNull() {
+		data.KernelArgs = types.ListValueMust(types.StringType, stringValues(set.KernelArgs))
+	}
+	if set.ProviderData != "" || !data.ProviderData.IsNull() {
+		data.ProviderData = types.StringValue(set.ProviderData)
+	}
+
+	machines, err := r.client.GetMachineRequestSetMachines(data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read machine request set machines, got error: %s", err))
+		return
+	}
+
+	data.Machines = types.ListValueMust(types.StringType, stringValues(machines))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineRequestSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniMachineRequestSetResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineRequestSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniMachineRequestSetResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := machineRequestSetTimeout(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteMachineRequestSet(data.ID.ValueString(), timeout); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete machine request set, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniMachineRequestSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniMachineRequestSetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniMachineRequestSetResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.MachineCount.IsNull() && !data.MachineCount.IsUnknown() && data.MachineCount.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("machine_count"), "Invalid Attribute Value",
+			fmt.Sprintf("machine_count cannot be negative, got %d", data.MachineCount.ValueInt64()))
+	}
+
+	for key := range data.MetaValues.Elements() {
+		if _, err := parseMetaKey(key); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("meta_values").AtMapKey(key), "Invalid Attribute Value", err.Error())
+		}
+	}
+
+	if !data.Timeout.IsNull() && !data.Timeout.IsUnknown() {
+		if timeout, err := time.ParseDuration(data.Timeout.ValueString()); err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("timeout"), "Invalid Attribute Value",
+				fmt.Sprintf("timeout must be a positive duration like 30m, got %q", data.Timeout.ValueString()))
+		}
+	}
+}
+
+// apply sends the machine request set to Omni and waits for its machines to register.
+func (r *OmniMachineRequestSetResource) apply(ctx context.Context, data *OmniMachineRequestSetResourceModel) diag.Diagnostics {
+	set, diags := machineRequestSetFromModel(ctx, *data)
+	if diags.HasError() {
+		return diags
+	}
+
+	timeout, d := machineRequestSetTimeout(*data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := r.client.ApplyMachineRequestSet(data.ID.ValueString(), set); err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to apply machine request set, got error: %s", err))
+		return diags
+	}
+
+	machines, err := r.client.WaitMachineRequestSetMachines(data.ID.ValueString(), set.MachineCount, timeout)
+	if err != nil {
+		diags.AddError("client Error", fmt.Sprintf("machines of the machine request set are not registered, got error: %s", err))
+		return diags
+	}
+
+	data.Machines = types.ListValueMust(types.StringType, stringValues(machines))
+
+	return diags
+}
+
+func machineRequestSetFromModel(ctx context.Context, data OmniMachineRequestSetResourceModel) (omniapi.MachineRequestSet, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	set := omniapi.MachineRequestSet{
+		ProviderID:   data.ProviderID.ValueString(),
+		MachineCount: int(data.MachineCount.ValueInt64()),
+		TalosVersion: data.TalosVersion.ValueString(),
+		MetaValues:   map[uint32]string{},
+		ProviderData: data.ProviderData.ValueString(),
+	}
+
+	if !data.Extensions.IsNull() {
+		diags.Append(data.Extensions.ElementsAs(ctx, &set.Extensions, false)...)
+	}
+
+	if !data.KernelArgs.IsNull() {
+		diags.Append(data.KernelArgs.ElementsAs(ctx, &set.KernelArgs, false)...)
+	}
+
+	meta := map[string]string{}
+	if !data.MetaValues.IsNull() {
+		diags.Append(data.MetaValues.ElementsAs(ctx, &meta, false)...)
+	}
+
+	for key, value := range meta {
+		k, err := parseMetaKey(key)
+		if err != nil {
+			diags.AddAttributeError(path.Root("meta_values").AtMapKey(key), "Invalid Attribute Value", err.Error())
+			continue
+		}
+
+		set.MetaValues[k] = value
+	}
+
+	return set, diags
+}
+
+func machineRequestSetTimeout(data OmniMachineRequestSetResourceModel) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if data.Timeout.IsNull() {
+		return defaultMachineRequestSetTimeout, diags
+	}
+
+	timeout, err := time.ParseDuration(data.Timeout.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("timeout"), "Invalid Attribute Value", err.Error())
+	}
+
+	return timeout, diags
+}
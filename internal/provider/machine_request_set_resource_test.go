@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccInfraProviderID is the infra provider provisioning the test machines, a KubeVirt provider by default.
+func testAccInfraProviderID() string {
+	if id := os.Getenv("OMNI_TEST_INFRA_PROVIDER"); id != "" {
+		return id
+	}
+
+	return "kubevirt"
+}
+
+func TestAccOmniMachineRequestSetResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccOmniMachineRequestSetResourceConfig(-1),
+				ExpectError: regexp.MustCompile("machine_count cannot be negative"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniMachineRequestSetResourceConfig(1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_request_set.test", "id", "tf-test"),
+					resource.TestCheckResourceAttr("omni_machine_request_set.test", "machines.#", "1"),
+				),
+			},
+			// Scale up testing
+			{
+				Config: testAccOmniMachineRequestSetResourceConfig(2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_request_set.test", "machines.#", "2"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "omni_machine_request_set.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"meta_values", "timeout"},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniMachineRequestSetResourceConfig(count int) string {
+	return fmt.Sprintf(`
+resource "omni_machine_request_set" "test" {
+  name          = "tf-test"
+  provider_id   = %q
+  talos_version = "v1.10.0"
+  kernel_args   = ["net.ifnames=0"]
+  meta_values = {
+    "0x0c" = "machineLabels: {pool: tf-test}"
+  }
+  machine_count = %d
+}
+`, testAccInfraProviderID(), count)
+}
+
+func TestMachineRequestSetFromModel(t *testing.T) {
+	data := OmniMachineRequestSetResourceModel{
+		ProviderID:   types.StringValue("kubevirt"),
+		TalosVersion: types.StringValue("v1.10.0"),
+		MachineCount: types.Int64Value(3),
+		Extensions:   types.ListNull(types.StringType),
+		KernelArgs:   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("net.ifnames=0")}),
+		MetaValues: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"0x0c": types.StringValue("machineLabels: {pool: a}"),
+		}),
+		ProviderData: types.StringNull(),
+	}
+
+	set, diags := machineRequestSetFromModel(context.Background(), data)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if set.MachineCount != 3 || set.ProviderID != "kubevirt" || len(set.Extensions) != 0 {
+		t.Errorf("unexpected machine request set %+v", set)
+	}
+
+	if len(set.KernelArgs) != 1 || set.KernelArgs[0] != "net.ifnames=0" {
+		t.Errorf("unexpected kernel args %v", set.KernelArgs)
+	}
+
+	if set.MetaValues[12] != "machineLabels: {pool: a}" {
+		t.Errorf("unexpected META values %v", set.MetaValues)
+	}
+}
@@ -0,0 +1,705 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+	"gopkg.in/yaml.v3"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+const (
+	machineSetRoleWorker       = "worker"
+	machineSetRoleControlPlane = "controlplane"
+
+	machineSetStrategyRolling = "rolling"
+	machineSetStrategyUnset   = "unset"
+
+	defaultMachineSetReadyTimeout = 30 * time.Minute
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniMachineSetResource{}
+var _ resource.ResourceWithImportState = &OmniMachineSetResource{}
+var _ resource.ResourceWithValidateConfig = &OmniMachineSetResource{}
+
+func NewOmniMachineSetResource() resource.Resource {
+	return &OmniMachineSetResource{}
+}
+
+// OmniMachineSetResource defines the resource implementation.
+type OmniMachineSetResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniMachineSetResourceModel describes the resource data model.
+type OmniMachineSetResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	Cluster        types.String `tfsdk:"cluster"`
+	Name           types.String `tfsdk:"name"`
+	Role           types.String `tfsdk:"role"`
+	Machines       types.Set    `tfsdk:"machines"`
+	MachineClass   types.Object `tfsdk:"machine_class"`
+	UpdateStrategy types.Object `tfsdk:"update_strategy"`
+	DeleteStrategy types.Object `tfsdk:"delete_strategy"`
+	Patches        types.List   `tfsdk:"patches"`
+	ReadyTimeout   types.String `tfsdk:"ready_timeout"`
+
+	Phase           types.String `tfsdk:"phase"`
+	Ready           types.Bool   `tfsdk:"ready"`
+	MachinesTotal   types.Int64  `tfsdk:"machines_total"`
+	MachinesHealthy types.Int64  `tfsdk:"machines_healthy"`
+}
+
+// MachineClassAllocationModel describes the machine_class attribute of omni_machine_set.
+type MachineClassAllocationModel struct {
+	Name  types.String `tfsdk:"name"`
+	Count types.Int64  `tfsdk:"count"`
+}
+
+var machineClassAllocationAttrTypes = map[string]attr.Type{
+	"name":  types.StringType,
+	"count": types.Int64Type,
+}
+
+// MachineSetStrategyModel describes the update_strategy and delete_strategy attributes of omni_machine_set.
+type MachineSetStrategyModel struct {
+	Type           types.String `tfsdk:"type"`
+	MaxParallelism types.Int64  `tfsdk:"max_parallelism"`
+}
+
+var machineSetStrategyAttrTypes = map[string]attr.Type{
+	"type":            types.StringType,
+	"max_parallelism": types.Int64Type,
+}
+
+// PatchModel describes an element of the patches attribute of omni_machine_set.
+type PatchModel struct {
+	Name   types.String `tfsdk:"name"`
+	Inline types.String `tfsdk:"inline"`
+}
+
+var patchAttrTypes = map[string]attr.Type{
+	"name":   types.StringType,
+	"inline": types.StringType,
+}
+
+func (r *OmniMachineSetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_set"
+}
+
+func machineSetStrategySchema(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Strategy type: `rolling` or `unset`",
+				Required:            true,
+			},
+			"max_parallelism": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of machines handled at the same time by a rolling strategy",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *OmniMachineSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_machine_set resource. Machine sets managed here are left untouched by the `omni_cluster` template syncs",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Machine set ID (`<cluster>-<name>`)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "Name of the cluster",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the machine set, `control-planes` for the control plane role",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "Role of the machines: `worker` or `controlplane`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"machines": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "UUIDs of the machines of the set. Conflicts with `machine_class`",
+				Optional:            true,
+			},
+			"machine_class": schema.SingleNestedAttribute{
+				MarkdownDescription: "Allocate the machines from a machine class. Conflicts with `machines`",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the machine class",
+						Required:            true,
+					},
+					"count": schema.Int64Attribute{
+						MarkdownDescription: "Number of machines to allocate",
+						Required:            true,
+					},
+				},
+			},
+			"update_strategy": machineSetStrategySchema("How machines are updated (default `rolling`)"),
+			"delete_strategy": machineSetStrategySchema("How machines are removed (default `unset`, all at once)"),
+			"patches": schema.ListNestedAttribute{
+				MarkdownDescription: "Config patches applied to the machines of the set. Names must be unique, patches are applied in name order",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Patch name",
+							Required:            true,
+						},
+						"inline": schema.StringAttribute{
+							MarkdownDescription: "Patch content in YAML",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"ready_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum duration to wait for the machine set to be ready on create and update (default `30m`)",
+				Optional:            true,
+			},
+			"phase": schema.StringAttribute{
+				MarkdownDescription: "Machine set phase reported by Omni (ScalingUp, Running, ...)",
+				Computed:            true,
+			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the machine set is ready",
+				Computed:            true,
+			},
+			"machines_total": schema.Int64Attribute{
+				MarkdownDescription: "Number of machines in the machine set",
+				Computed:            true,
+			},
+			"machines_healthy": schema.Int64Attribute{
+				MarkdownDescription: "Number of healthy machines in the machine set",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *OmniMachineSetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniMachineSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniMachineSetResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(machineSetID(data))
+
+	applied, diags := r.apply(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if !applied {
+		return
+	}
+
+	tflog.Trace(ctx, "create a resource omni_machine_set")
+
+	// Save data into Terraform state, even on a readiness failure as the machine set exists
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniMachineSetResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ms, err := r.client.GetMachineSet(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read machine set, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(machineSetToModel(ctx, ms, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.readMachineSetStatus(&data); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to get machine set status, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniMachineSetResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied, diags := r.apply(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if !applied {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniMachineSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniMachineSetResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteMachineSet(data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete machine set, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniMachineSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniMachineSetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniMachineSetResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch data.Role.ValueString() {
+	case machineSetRoleWorker:
+	case machineSetRoleControlPlane:
+		if !data.Name.IsUnknown() && data.Name.ValueString() != omni.ControlPlanesIDSuffix {
+			resp.Diagnostics.AddAttributeError(path.Root("name"), "Invalid Attribute Value",
+				fmt.Sprintf("the control plane machine set must be named %q", omni.ControlPlanesIDSuffix))
+		}
+	default:
+		if !data.Role.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(path.Root("role"), "Invalid Attribute Value",
+				fmt.Sprintf("role must be %s or %s, got %q", machineSetRoleWorker, machineSetRoleControlPlane, data.Role.ValueString()))
+		}
+	}
+
+	if !data.Machines.IsUnknown() && !data.MachineClass.IsUnknown() && data.Machines.IsNull() == data.MachineClass.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("machines"), "Invalid Attribute Combination",
+			"exactly one of machines or machine_class must be set")
+	}
+
+	if !data.MachineClass.IsNull() && !data.MachineClass.IsUnknown() {
+		var class MachineClassAllocationModel
+		resp.Diagnostics.Append(data.MachineClass.As(ctx, &class, basetypes.ObjectAsOptions{})...)
+		if !class.Count.IsUnknown() && class.Count.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("machine_class").AtName("count"), "Invalid Attribute Value", "count must not be negative")
+		}
+	}
+
+	resp.Diagnostics.Append(validateMachineSetStrategy(ctx, "update_strategy", data.UpdateStrategy)...)
+	resp.Diagnostics.Append(validateMachineSetStrategy(ctx, "delete_strategy", data.DeleteStrategy)...)
+
+	patches, diags := patchesFrom(ctx, data.Patches)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(validatePatchNames(patches)...)
+	for i, patch := range patches {
+		if patch.Inline.IsUnknown() {
+			continue
+		}
+
+		if err := validatePatch(patch.Inline.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("patches").AtListIndex(i).AtName("inline"), "Invalid Attribute Value",
+				fmt.Sprintf("patch %q: %s", patch.Name.ValueString(), err))
+		}
+	}
+
+	if !data.ReadyTimeout.IsNull() && !data.ReadyTimeout.IsUnknown() {
+		if _, err := time.ParseDuration(data.ReadyTimeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ready_timeout"), "Invalid Attribute Value",
+				fmt.Sprintf("ready_timeout must be a duration like 30m: %s", err))
+		}
+	}
+}
+
+// apply sends the machine set to Omni and waits for it to be ready. It tells whether the machine set
+// was applied, a readiness failure is reported in the diagnostics of an applied machine set.
+func (r *OmniMachineSetResource) apply(ctx context.Context, data *OmniMachineSetResourceModel) (bool, diag.Diagnostics) {
+	ms, diags := machineSetFromModel(ctx, *data)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	timeout := defaultMachineSetReadyTimeout
+	if !data.ReadyTimeout.IsNull() {
+		var err error
+		if timeout, err = time.ParseDuration(data.ReadyTimeout.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("ready_timeout"), "Invalid Attribute Value", err.Error())
+			return false, diags
+		}
+	}
+
+	data.Phase = types.StringNull()
+	data.Ready = types.BoolValue(false)
+	data.MachinesTotal = types.Int64Null()
+	data.MachinesHealthy = types.Int64Null()
+
+	if err := r.client.ApplyMachineSet(ms); err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to apply machine set, got error: %s", err))
+		return false, diags
+	}
+
+	requested := ms.MachineCount
+	if ms.MachineClass == "" {
+		requested = uint32(len(ms.Machines))
+	}
+
+	if _, err := r.client.WaitForMachineSetReady(ms.ID, requested, timeout); err != nil {
+		diags.AddError("client Error", fmt.Sprintf("machine set is not ready, got error: %s", err))
+	}
+
+	if err := r.readMachineSetStatus(data); err != nil {
+		diags.AddError("client Error", fmt.Sprintf("unable to get machine set status, got error: %s", err))
+	}
+
+	return true, diags
+}
+
+func (r *OmniMachineSetResource) readMachineSetStatus(data *OmniMachineSetResourceModel) error {
+	status, err := r.client.GetMachineSetStatus(data.ID.ValueString())
+	if err != nil {
+		return err
+	}
+
+	spec := status.TypedSpec().Value
+	data.Phase = types.StringValue(spec.Phase.String())
+	data.Ready = types.BoolValue(spec.Ready)
+	data.MachinesTotal = types.Int64Value(int64(spec.GetMachines().GetTotal()))
+	data.MachinesHealthy = types.Int64Value(int64(spec.GetMachines().GetHealthy()))
+
+	return nil
+}
+
+func machineSetID(data OmniMachineSetResourceModel) string {
+	if data.Role.ValueString() == machineSetRoleControlPlane {
+		return omni.ControlPlanesResourceID(data.Cluster.ValueString())
+	}
+
+	return omni.AdditionalWorkersResourceID(data.Cluster.ValueString(), data.Name.ValueString())
+}
+
+func validateMachineSetStrategy(ctx context.Context, attribute string, value types.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if value.IsNull() || value.IsUnknown() {
+		return diags
+	}
+
+	var strategy MachineSetStrategyModel
+	diags.Append(value.As(ctx, &strategy, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+
+	switch strategy.Type.ValueString() {
+	case machineSetStrategyRolling, machineSetStrategyUnset:
+	default:
+		if !strategy.Type.IsUnknown() {
+			diags.AddAttributeError(path.Root(attribute).AtName("type"), "Invalid Attribute Value",
+				fmt.Sprintf("type must be %s or %s, got %q", machineSetStrategyRolling, machineSetStrategyUnset, strategy.Type.ValueString()))
+		}
+	}
+
+	if !strategy.MaxParallelism.IsNull() && !strategy.MaxParallelism.IsUnknown() {
+		if strategy.MaxParallelism.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root(attribute).AtName("max_parallelism"), "Invalid Attribute Value", "max_parallelism must not be negative")
+		}
+		if strategy.Type.ValueString() == machineSetStrategyUnset {
+			diags.AddAttributeError(path.Root(attribute).AtName("max_parallelism"), "Invalid Attribute Combination",
+				"max_parallelism only applies to the rolling strategy")
+		}
+	}
+
+	return diags
+}
+
+// validatePatch checks that a patch is a YAML mapping accepted by Omni as a Talos config patch.
+func validatePatch(patch string) error {
+	var content map[string]any
+	if err := yaml.Unmarshal([]byte(patch), &content); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	if content == nil {
+		return fmt.Errorf("patch must be a YAML mapping")
+	}
+
+	return omni.ValidateConfigPatch([]byte(patch))
+}
+
+// yamlEqual tells whether two YAML documents hold the same content, whatever their formatting.
+func yamlEqual(a, b string) bool {
+	var va, vb any
+	if yaml.Unmarshal([]byte(a), &va) != nil || yaml.Unmarshal([]byte(b), &vb) != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+
+	return reflect.DeepEqual(va, vb)
+}
+
+// validatePatchNames makes sure patch names are unique, patches are keyed by name in Omni.
+func validatePatchNames(patches []PatchModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	seen := map[string]struct{}{}
+	for i, patch := range patches {
+		if patch.Name.IsUnknown() {
+			continue
+		}
+
+		if _, ok := seen[patch.Name.ValueString()]; ok {
+			diags.AddAttributeError(path.Root("patches").AtListIndex(i).AtName("name"), "Invalid Attribute Value",
+				fmt.Sprintf("patch name %q is used more than once", patch.Name.ValueString()))
+		}
+		seen[patch.Name.ValueString()] = struct{}{}
+	}
+
+	return diags
+}
+
+// patchesToModel builds the patches attribute out of the patches read from Omni, which come back in
+// name order: they are listed in the order of the current value, the ones missing from it last.
+// The current inline text is kept when it holds the same content.
+func patchesToModel(ctx context.Context, value types.List, patches []omniapi.Patch) (types.List, diag.Diagnostics) {
+	current, diags := patchesFrom(ctx, value)
+
+	if len(patches) == 0 && value.IsNull() {
+		return value, diags
+	}
+
+	remaining := make(map[string]omniapi.Patch, len(patches))
+	for _, patch := range patches {
+		remaining[patch.Name] = patch
+	}
+
+	models := make([]PatchModel, 0, len(patches))
+	for _, c := range current {
+		patch, ok := remaining[c.Name.ValueString()]
+		if !ok {
+			continue
+		}
+		delete(remaining, patch.Name)
+
+		inline := patch.Data
+		if yamlEqual(c.Inline.ValueString(), patch.Data) {
+			inline = c.Inline.ValueString()
+		}
+
+		models = append(models, PatchModel{Name: types.StringValue(patch.Name), Inline: types.StringValue(inline)})
+	}
+
+	for _, patch := range patches {
+		if _, ok := remaining[patch.Name]; ok {
+			models = append(models, PatchModel{Name: types.StringValue(patch.Name), Inline: types.StringValue(patch.Data)})
+		}
+	}
+
+	list, d := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: patchAttrTypes}, models)
+	diags.Append(d...)
+
+	return list, diags
+}
+
+func patchesFrom(ctx context.Context, value types.List) ([]PatchModel, diag.Diagnostics) {
+	var patches []PatchModel
+
+	if value.IsNull() || value.IsUnknown() {
+		return nil, nil
+	}
+
+	diags := value.ElementsAs(ctx, &patches, false)
+
+	return patches, diags
+}
+
+func strategyFromModel(ctx context.Context, value types.Object, defaultStrategy omniapi.MachineSetStrategy) (omniapi.MachineSetStrategy, diag.Diagnostics) {
+	if value.IsNull() {
+		return defaultStrategy, nil
+	}
+
+	var m MachineSetStrategyModel
+	diags := value.As(ctx, &m, basetypes.ObjectAsOptions{})
+
+	return omniapi.MachineSetStrategy{
+		Rolling:        m.Type.ValueString() == machineSetStrategyRolling,
+		MaxParallelism: uint32(m.MaxParallelism.ValueInt64()),
+	}, diags
+}
+
+// strategyToModel returns the strategy attribute value. A strategy left to its default stays null.
+func strategyToModel(ctx context.Context, current types.Object, strategy, defaultStrategy omniapi.MachineSetStrategy) (types.Object, diag.Diagnostics) {
+	if current.IsNull() && strategy == defaultStrategy {
+		return current, nil
+	}
+
+	m := MachineSetStrategyModel{
+		Type:           types.StringValue(machineSetStrategyUnset),
+		MaxParallelism: types.Int64Null(),
+	}
+	if strategy.Rolling {
+		m.Type = types.StringValue(machineSetStrategyRolling)
+	}
+	if strategy.MaxParallelism != 0 {
+		m.MaxParallelism = types.Int64Value(int64(strategy.MaxParallelism))
+	}
+
+	return types.ObjectValueFrom(ctx, machineSetStrategyAttrTypes, m)
+}
+
+var (
+	defaultUpdateStrategy = omniapi.MachineSetStrategy{Rolling: true}
+	defaultDeleteStrategy = omniapi.MachineSetStrategy{}
+)
+
+func machineSetFromModel(ctx context.Context, data OmniMachineSetResourceModel) (omniapi.MachineSet, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ms := omniapi.MachineSet{
+		ID:           data.ID.ValueString(),
+		Cluster:      data.Cluster.ValueString(),
+		ControlPlane: data.Role.ValueString() == machineSetRoleControlPlane,
+	}
+
+	if !data.Machines.IsNull() {
+		diags.Append(data.Machines.ElementsAs(ctx, &ms.Machines, false)...)
+	}
+
+	if !data.MachineClass.IsNull() {
+		var class MachineClassAllocationModel
+		diags.Append(data.MachineClass.As(ctx, &class, basetypes.ObjectAsOptions{})...)
+		ms.MachineClass = class.Name.ValueString()
+		ms.MachineCount = uint32(class.Count.ValueInt64())
+	}
+
+	var d diag.Diagnostics
+	ms.UpdateStrategy, d = strategyFromModel(ctx, data.UpdateStrategy, defaultUpdateStrategy)
+	diags.Append(d...)
+	ms.DeleteStrategy, d = strategyFromModel(ctx, data.DeleteStrategy, defaultDeleteStrategy)
+	diags.Append(d...)
+
+	patches, d := patchesFrom(ctx, data.Patches)
+	diags.Append(d...)
+	for _, patch := range patches {
+		ms.Patches = append(ms.Patches, omniapi.Patch{Name: patch.Name.ValueString(), Data: patch.Inline.ValueString()})
+	}
+
+	return ms, diags
+}
+
+// machineSetToModel refreshes the model from the machine set found in Omni. Patches equal
+// to the configured ones keep their configured formatting.
+func machineSetToModel(ctx context.Context, ms *omniapi.MachineSet, data *OmniMachineSetResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.Cluster = types.StringValue(ms.Cluster)
+	data.Name = types.StringValue(strings.TrimPrefix(ms.ID, ms.Cluster+"-"))
+	data.Role = types.StringValue(machineSetRoleWorker)
+	if ms.ControlPlane {
+		data.Role = types.StringValue(machineSetRoleControlPlane)
+	}
+
+	if ms.MachineClass != "" {
+		var d diag.Diagnostics
+		data.MachineClass, d = types.ObjectValueFrom(ctx, machineClassAllocationAttrTypes, MachineClassAllocationModel{
+			Name:  types.StringValue(ms.MachineClass),
+			Count: types.Int64Value(int64(ms.MachineCount)),
+		})
+		diags.Append(d...)
+		data.Machines = types.SetNull(types.StringType)
+	} else {
+		data.MachineClass = types.ObjectNull(machineClassAllocationAttrTypes)
+		if len(ms.Machines) > 0 || !data.Machines.IsNull() {
+			data.Machines = types.SetValueMust(types.StringType, stringValues(ms.Machines))
+		}
+	}
+
+	var d diag.Diagnostics
+	data.UpdateStrategy, d = strategyToModel(ctx, data.UpdateStrategy, ms.UpdateStrategy, defaultUpdateStrategy)
+	diags.Append(d...)
+	data.DeleteStrategy, d = strategyToModel(ctx, data.DeleteStrategy, ms.DeleteStrategy, defaultDeleteStrategy)
+	diags.Append(d...)
+
+	data.Patches, d = patchesToModel(ctx, data.Patches, ms.Patches)
+	diags.Append(d...)
+
+	return diags
+}
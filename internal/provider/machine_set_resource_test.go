@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+func TestAccOmniMachineSetResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccOmniMachineSetResourceConfig(1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_set.test", "id", "test-cluster-1-pool"),
+					resource.TestCheckResourceAttr("omni_machine_set.test", "ready", "true"),
+					resource.TestCheckResourceAttr("omni_machine_set.test", "machines_total", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "omni_machine_set.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ready_timeout"},
+			},
+			// Update and Read testing
+			{
+				Config: testAccOmniMachineSetResourceConfig(2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_machine_set.test", "machine_class.count", "2"),
+					resource.TestCheckResourceAttr("omni_machine_set.test", "machines_total", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniMachineSetResourceConfig(count int) string {
+	return fmt.Sprintf(`
+resource "omni_machine_set" "test" {
+  cluster = "test-cluster-1"
+  name    = "pool"
+  role    = "worker"
+
+  machine_class = {
+    name  = "workers"
+    count = %d
+  }
+
+  update_strategy = {
+    type            = "rolling"
+    max_parallelism = 1
+  }
+
+  patches = [
+    {
+      name   = "labels"
+      inline = <<-EOT
+        machine:
+          nodeLabels:
+            pool: test
+      EOT
+    },
+  ]
+}
+`, count)
+}
+
+func TestMachineSetToModel(t *testing.T) {
+	ctx := context.Background()
+
+	data := OmniMachineSetResourceModel{
+		UpdateStrategy: types.ObjectNull(machineSetStrategyAttrTypes),
+		DeleteStrategy: types.ObjectNull(machineSetStrategyAttrTypes),
+		Machines:       types.SetNull(types.StringType),
+		Patches: types.ListValueMust(types.ObjectType{AttrTypes: patchAttrTypes}, []attr.Value{
+			types.ObjectValueMust(patchAttrTypes, map[string]attr.Value{
+				"name":   types.StringValue("labels"),
+				"inline": types.StringValue("machine:\n    nodeLabels: {pool: test}\n"),
+			}),
+		}),
+	}
+
+	ms := &omniapi.MachineSet{
+		ID:             "test-cluster-1-pool",
+		Cluster:        "test-cluster-1",
+		Machines:       []string{"a7a3b4c2-0000-0000-0000-000000000001"},
+		UpdateStrategy: defaultUpdateStrategy,
+		DeleteStrategy: omniapi.MachineSetStrategy{Rolling: true, MaxParallelism: 2},
+		Patches:        []omniapi.Patch{{Name: "labels", Data: "machine:\n  nodeLabels:\n    pool: test\n"}},
+	}
+
+	if diags := machineSetToModel(ctx, ms, &data); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if data.Name.ValueString() != "pool" || data.Role.ValueString() != machineSetRoleWorker {
+		t.Errorf("unexpected name %q and role %q", data.Name.ValueString(), data.Role.ValueString())
+	}
+	if len(data.Machines.Elements()) != 1 {
+		t.Errorf("expected 1 machine, got %d", len(data.Machines.Elements()))
+	}
+	if !data.UpdateStrategy.IsNull() {
+		t.Errorf("expected the default update strategy to stay null, got %s", data.UpdateStrategy)
+	}
+	if data.DeleteStrategy.IsNull() {
+		t.Errorf("expected the delete strategy to be refreshed")
+	}
+
+	var patches []PatchModel
+	data.Patches.ElementsAs(ctx, &patches, false)
+	if patches[0].Inline.ValueString() != "machine:\n    nodeLabels: {pool: test}\n" {
+		t.Errorf("expected an equivalent patch to keep its configured formatting, got %q", patches[0].Inline.ValueString())
+	}
+
+	ms.Patches[0].Data = "machine:\n  nodeLabels:\n    pool: other\n"
+	if diags := machineSetToModel(ctx, ms, &data); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	data.Patches.ElementsAs(ctx, &patches, false)
+	if patches[0].Inline.ValueString() != ms.Patches[0].Data {
+		t.Errorf("expected the patch edited in Omni to show up as drift, got %q", patches[0].Inline.ValueString())
+	}
+}
+
+func TestPatchesToModelKeepsConfiguredOrder(t *testing.T) {
+	ctx := context.Background()
+
+	current := types.ListValueMust(types.ObjectType{AttrTypes: patchAttrTypes}, []attr.Value{
+		types.ObjectValueMust(patchAttrTypes, map[string]attr.Value{
+			"name":   types.StringValue("sysctls"),
+			"inline": types.StringValue("machine: {sysctls: {}}\n"),
+		}),
+		types.ObjectValueMust(patchAttrTypes, map[string]attr.Value{
+			"name":   types.StringValue("labels"),
+			"inline": types.StringValue("machine: {nodeLabels: {}}\n"),
+		}),
+	})
+
+	// Omni returns the patches in name order, along with one created out of Terraform.
+	list, diags := patchesToModel(ctx, current, []omniapi.Patch{
+		{Name: "extra", Data: "machine: {}\n"},
+		{Name: "labels", Data: "machine: {nodeLabels: {}}\n"},
+		{Name: "sysctls", Data: "machine: {sysctls: {}}\n"},
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	var patches []PatchModel
+	list.ElementsAs(ctx, &patches, false)
+
+	var names []string
+	for _, patch := range patches {
+		names = append(names, patch.Name.ValueString())
+	}
+
+	if strings.Join(names, ",") != "sysctls,labels,extra" {
+		t.Errorf("expected the configured order followed by unknown patches, got %v", names)
+	}
+
+	if diags := validatePatchNames(append(patches, patches[0])); !diags.HasError() {
+		t.Error("expected duplicated patch names to be rejected")
+	}
+}
@@ -81,6 +81,18 @@ func (p *OmniProvider) Resources(ctx context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		NewOmniClusterResource,
 		NewOmniKubeconfigResource,
+		NewOmniMachineSetResource,
+		NewOmniConfigPatchResource,
+		NewOmniMachineLabelsResource,
+		NewOmniMachineClassResource,
+		NewOmniServiceAccountResource,
+		NewOmniUserResource,
+		NewOmniEtcdBackupS3ConfigurationResource,
+		NewOmniEtcdManualBackupResource,
+		NewOmniSchematicResource,
+		NewOmniInfraProviderResource,
+		NewOmniMachineRequestSetResource,
+		NewOmniClusterMachineResource,
 	}
 }
 
@@ -88,6 +100,8 @@ func (p *OmniProvider) DataSources(ctx context.Context) []func() datasource.Data
 	return []func() datasource.DataSource{
 		NewOmniMachineDataSource,
 		NewOmniTalosconfigDataSource,
+		NewOmniInstallationMediaDataSource,
+		NewOmniExposedServiceDataSource,
 	}
 }
 
@@ -0,0 +1,250 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniSchematicResource{}
+var _ resource.ResourceWithValidateConfig = &OmniSchematicResource{}
+
+func NewOmniSchematicResource() resource.Resource {
+	return &OmniSchematicResource{}
+}
+
+// OmniSchematicResource defines the resource implementation.
+type OmniSchematicResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniSchematicResourceModel describes the resource data model.
+type OmniSchematicResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	TalosVersion   types.String `tfsdk:"talos_version"`
+	Extensions     types.List   `tfsdk:"extensions"`
+	KernelArgs     types.List   `tfsdk:"kernel_args"`
+	MetaValues     types.Map    `tfsdk:"meta_values"`
+	SecureBoot     types.Bool   `tfsdk:"secure_boot"`
+	PXEURL         types.String `tfsdk:"pxe_url"`
+	InstallerImage types.String `tfsdk:"installer_image"`
+}
+
+func (r *OmniSchematicResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schematic"
+}
+
+func (r *OmniSchematicResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keep := []planmodifier.String{stringplanmodifier.UseStateForUnknown()}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_schematic resource. Registers an Image Factory schematic including the SideroLink parameters of Omni. " +
+			"Schematic IDs are derived from their content, any change creates a new schematic",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Schematic ID",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"talos_version": schema.StringAttribute{
+				MarkdownDescription: "Talos version of the images, e.g. `v1.10.0`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"extensions": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "System extensions, e.g. `siderolabs/iscsi-tools`",
+				Optional:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"kernel_args": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Extra kernel arguments",
+				Optional:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"meta_values": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Talos META values by key, keys are decimal or hexadecimal (`0x0a`) integers",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"secure_boot": schema.BoolAttribute{
+				MarkdownDescription: "Build secure boot images",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"pxe_url": schema.StringAttribute{
+				MarkdownDescription: "PXE boot URL",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"installer_image": schema.StringAttribute{
+				MarkdownDescription: "Installer image, to be used in the machine install configuration and upgrades",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+		},
+	}
+}
+
+func (r *OmniSchematicResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniSchematicResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniSchematicResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schematic, diags := schematicFromModel(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	images, err := r.client.CreateSchematic(schematic)
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create schematic, got error: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(images.ID)
+	data.PXEURL = types.StringValue(images.PXEURL)
+	data.InstallerImage = types.StringValue(images.InstallerImage)
+
+	tflog.Trace(ctx, "create a resource omni_schematic")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniSchematicResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Schematics are immutable and cannot be listed back, the state is kept as it is.
+}
+
+func (r *OmniSchematicResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every attribute requires a replacement.
+	var data OmniSchematicResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniSchematicResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Schematics stay in the Image Factory, machines may still be running their images.
+}
+
+func (r *OmniSchematicResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniSchematicResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for key := range data.MetaValues.Elements() {
+		if _, err := parseMetaKey(key); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("meta_values").AtMapKey(key), "Invalid Attribute Value", err.Error())
+		}
+	}
+}
+
+func schematicFromModel(ctx context.Context, data OmniSchematicResourceModel) (omniapi.Schematic, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	schematic := omniapi.Schematic{
+		TalosVersion: data.TalosVersion.ValueString(),
+		SecureBoot:   data.SecureBoot.ValueBool(),
+		MetaValues:   map[uint32]string{},
+	}
+
+	if !data.Extensions.IsNull() {
+		diags.Append(data.Extensions.ElementsAs(ctx, &schematic.Extensions, false)...)
+	}
+
+	if !data.KernelArgs.IsNull() {
+		diags.Append(data.KernelArgs.ElementsAs(ctx, &schematic.KernelArgs, false)...)
+	}
+
+	meta := map[string]string{}
+	if !data.MetaValues.IsNull() {
+		diags.Append(data.MetaValues.ElementsAs(ctx, &meta, false)...)
+	}
+
+	for key, value := range meta {
+		k, err := parseMetaKey(key)
+		if err != nil {
+			diags.AddAttributeError(path.Root("meta_values").AtMapKey(key), "Invalid Attribute Value", err.Error())
+			continue
+		}
+
+		schematic.MetaValues[k] = value
+	}
+
+	return schematic, diags
+}
+
+// parseMetaKey parses a Talos META key, META keys are single bytes.
+func parseMetaKey(key string) (uint32, error) {
+	k, err := strconv.ParseUint(key, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("META key %q must be an integer between 0 and 255 (0xff)", key)
+	}
+
+	return uint32(k), nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOmniSchematicResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccOmniSchematicResourceConfig("net.ifnames=0", "0x1ff"),
+				ExpectError: regexp.MustCompile("must be an integer between 0 and 255"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniSchematicResourceConfig("net.ifnames=0", "0x0c"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("omni_schematic.test", "id"),
+					resource.TestCheckResourceAttrSet("omni_schematic.test", "pxe_url"),
+					resource.TestMatchResourceAttr("omni_schematic.test", "installer_image", regexp.MustCompile(`/installer/[0-9a-f]+:v1\.10\.0$`)),
+				),
+			},
+			// A content change replaces the schematic
+			{
+				Config: testAccOmniSchematicResourceConfig("net.ifnames=1", "0x0c"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_schematic.test", "kernel_args.0", "net.ifnames=1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniSchematicResourceConfig(kernelArg, metaKey string) string {
+	return fmt.Sprintf(`
+resource "omni_schematic" "test" {
+  talos_version = "v1.10.0"
+  extensions    = ["siderolabs/iscsi-tools"]
+  kernel_args   = [%q]
+  meta_values = {
+    %q = "machineLabels: {profile: storage}"
+  }
+}
+`, kernelArg, metaKey)
+}
+
+func TestParseMetaKey(t *testing.T) {
+	for key, expected := range map[string]uint32{"10": 10, "0x0a": 10, "0xff": 255} {
+		got, err := parseMetaKey(key)
+		if err != nil || got != expected {
+			t.Errorf("parseMetaKey(%q) = %d, %v, expected %d", key, got, err, expected)
+		}
+	}
+
+	for _, key := range []string{"", "0x100", "-1", "labels"} {
+		if _, err := parseMetaKey(key); err == nil {
+			t.Errorf("parseMetaKey(%q) should fail", key)
+		}
+	}
+}
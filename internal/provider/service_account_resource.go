@@ -0,0 +1,358 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+const defaultServiceAccountTTL = 365 * 24 * time.Hour
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniServiceAccountResource{}
+var _ resource.ResourceWithImportState = &OmniServiceAccountResource{}
+var _ resource.ResourceWithValidateConfig = &OmniServiceAccountResource{}
+var _ resource.ResourceWithModifyPlan = &OmniServiceAccountResource{}
+
+func NewOmniServiceAccountResource() resource.Resource {
+	return &OmniServiceAccountResource{}
+}
+
+// OmniServiceAccountResource defines the resource implementation.
+type OmniServiceAccountResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniServiceAccountResourceModel describes the resource data model.
+type OmniServiceAccountResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Role         types.String `tfsdk:"role"`
+	TTL          types.String `tfsdk:"ttl"`
+	RotateBefore types.String `tfsdk:"rotate_before"`
+	Key          types.String `tfsdk:"key"`
+	PublicKeyID  types.String `tfsdk:"public_key_id"`
+	Expiration   types.String `tfsdk:"expiration"`
+}
+
+func (r *OmniServiceAccountResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_account"
+}
+
+func (r *OmniServiceAccountResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keep := []planmodifier.String{stringplanmodifier.UseStateForUnknown()}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_service_account resource. The key is renewed by an update when it expires within `rotate_before`",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Service account name",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the service account",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "Role of the service account (`Reader`, `Operator`, `Admin`, ...), the role of the provider credentials when not set",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.StringAttribute{
+				MarkdownDescription: "Lifetime of the generated keys (default `8760h`), a change applies from the next renewal",
+				Optional:            true,
+			},
+			"rotate_before": schema.StringAttribute{
+				MarkdownDescription: "Renew the key once it expires within this duration, e.g. `720h`. Keys are never renewed when not set",
+				Optional:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "Service account key, to be used as `OMNI_SERVICE_ACCOUNT_KEY`. Not available after an import until the next renewal",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers:       keep,
+			},
+			"public_key_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the public key of `key`",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+			"expiration": schema.StringAttribute{
+				MarkdownDescription: "Expiration date of `key` (RFC 3339)",
+				Computed:            true,
+				PlanModifiers:       keep,
+			},
+		},
+	}
+}
+
+func (r *OmniServiceAccountResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniServiceAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniServiceAccountResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ttl, diags := serviceAccountTTL(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := r.client.CreateServiceAccount(data.Name.ValueString(), data.Role.ValueString(), ttl)
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create service account, got error: %s", err))
+		return
+	}
+
+	data.ID = data.Name
+	setServiceAccountKey(&data, key)
+
+	if data.Role.IsUnknown() {
+		sa, err := r.client.GetServiceAccount(data.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read service account, got error: %s", err))
+			return
+		}
+
+		data.Role = types.StringValue(sa.Role)
+	}
+
+	tflog.Trace(ctx, "create a resource omni_service_account")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniServiceAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniServiceAccountResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sa, err := r.client.GetServiceAccount(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read service account, got error: %s", err))
+		return
+	}
+
+	data.Name = types.StringValue(sa.Name)
+	data.Role = types.StringValue(sa.Role)
+
+	// A key removed out of Terraform leaves no expiration, which plans a renewal when rotate_before is set.
+	// Imported service accounts track their latest key.
+	data.Expiration = types.StringNull()
+	if expiration, ok := sa.PublicKeys[data.PublicKeyID.ValueString()]; ok {
+		data.Expiration = types.StringValue(expiration.Format(time.RFC3339))
+	} else if data.PublicKeyID.IsNull() {
+		var latest time.Time
+		for id, expiration := range sa.PublicKeys {
+			if expiration.After(latest) {
+				latest = expiration
+				data.PublicKeyID = types.StringValue(id)
+				data.Expiration = types.StringValue(expiration.Format(time.RFC3339))
+			}
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniServiceAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniServiceAccountResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ModifyPlan leaves the key unknown when it has to be renewed, other changes only touch the Terraform state.
+	if data.Key.IsUnknown() {
+		ttl, diags := serviceAccountTTL(data)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		key, err := r.client.RenewServiceAccount(data.Name.ValueString(), ttl)
+		if err != nil {
+			resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to renew service account, got error: %s", err))
+			return
+		}
+
+		setServiceAccountKey(&data, key)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniServiceAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniServiceAccountResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DestroyServiceAccount(data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete service account, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniServiceAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniServiceAccountResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniServiceAccountResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ttl := defaultServiceAccountTTL
+	if !data.TTL.IsNull() && !data.TTL.IsUnknown() {
+		var err error
+		if ttl, err = time.ParseDuration(data.TTL.ValueString()); err != nil || ttl <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid Attribute Value",
+				fmt.Sprintf("ttl must be a positive duration like 8760h, got %q", data.TTL.ValueString()))
+			return
+		}
+	}
+
+	if !data.RotateBefore.IsNull() && !data.RotateBefore.IsUnknown() {
+		rotateBefore, err := time.ParseDuration(data.RotateBefore.ValueString())
+		switch {
+		case err != nil || rotateBefore <= 0:
+			resp.Diagnostics.AddAttributeError(path.Root("rotate_before"), "Invalid Attribute Value",
+				fmt.Sprintf("rotate_before must be a positive duration like 720h, got %q", data.RotateBefore.ValueString()))
+		case rotateBefore >= ttl:
+			resp.Diagnostics.AddAttributeError(path.Root("rotate_before"), "Invalid Attribute Value",
+				"rotate_before must be shorter than ttl, the key would be renewed on every apply")
+		}
+	}
+}
+
+// ModifyPlan plans a renewal of the key when it expires within rotate_before.
+func (r *OmniServiceAccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan OmniServiceAccountResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.RotateBefore.IsNull() || plan.RotateBefore.IsUnknown() {
+		return
+	}
+
+	rotateBefore, err := time.ParseDuration(plan.RotateBefore.ValueString())
+	if err != nil {
+		// Reported by ValidateConfig.
+		return
+	}
+
+	if !serviceAccountKeyExpiresWithin(state.Expiration, rotateBefore, time.Now()) {
+		return
+	}
+
+	tflog.Info(ctx, "service account key expires soon, planning a renewal", map[string]any{"name": state.Name.ValueString()})
+
+	for _, attr := range []string{"key", "public_key_id", "expiration"} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.StringUnknown())...)
+	}
+}
+
+func serviceAccountTTL(data OmniServiceAccountResourceModel) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if data.TTL.IsNull() {
+		return defaultServiceAccountTTL, diags
+	}
+
+	ttl, err := time.ParseDuration(data.TTL.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("ttl"), "Invalid Attribute Value", err.Error())
+	}
+
+	return ttl, diags
+}
+
+// serviceAccountKeyExpiresWithin tells whether the key expires before now + window, keys of unknown expiration included.
+func serviceAccountKeyExpiresWithin(expiration types.String, window time.Duration, now time.Time) bool {
+	if expiration.IsNull() || expiration.IsUnknown() {
+		return true
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, expiration.ValueString())
+	if err != nil {
+		return true
+	}
+
+	return now.Add(window).After(expiresAt)
+}
+
+func setServiceAccountKey(data *OmniServiceAccountResourceModel, key *omniapi.ServiceAccountKey) {
+	data.Key = types.StringValue(key.Key)
+	data.PublicKeyID = types.StringValue(key.PublicKeyID)
+	data.Expiration = types.StringValue(key.Expiration.Format(time.RFC3339))
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOmniServiceAccountResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccOmniServiceAccountResourceConfig("24h", "48h"),
+				ExpectError: regexp.MustCompile("rotate_before must be shorter than ttl"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniServiceAccountResourceConfig("24h", "1h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_service_account.test", "id", "terraform-acc-test"),
+					resource.TestCheckResourceAttr("omni_service_account.test", "role", "Reader"),
+					resource.TestCheckResourceAttrSet("omni_service_account.test", "key"),
+					resource.TestCheckResourceAttrSet("omni_service_account.test", "public_key_id"),
+					resource.TestCheckResourceAttrSet("omni_service_account.test", "expiration"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "omni_service_account.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"key", "ttl", "rotate_before"},
+			},
+			// A rotate_before window longer than the remaining lifetime renews the key
+			{
+				Config: testAccOmniServiceAccountResourceConfig("48h", "30h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("omni_service_account.test", "key"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniServiceAccountResourceConfig(ttl, rotateBefore string) string {
+	return fmt.Sprintf(`
+resource "omni_service_account" "test" {
+  name          = "terraform-acc-test"
+  role          = "Reader"
+  ttl           = %q
+  rotate_before = %q
+}
+`, ttl, rotateBefore)
+}
+
+func TestServiceAccountKeyExpiresWithin(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name       string
+		expiration types.String
+		window     time.Duration
+		expected   bool
+	}{
+		{"far", types.StringValue("2024-12-01T00:00:00Z"), 720 * time.Hour, false},
+		{"within window", types.StringValue("2024-06-20T00:00:00Z"), 720 * time.Hour, true},
+		{"expired", types.StringValue("2024-05-01T00:00:00Z"), time.Hour, true},
+		{"unknown key", types.StringNull(), time.Hour, true},
+		{"invalid date", types.StringValue("tomorrow"), time.Hour, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := serviceAccountKeyExpiresWithin(tc.expiration, tc.window, now); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/auth"
+
+	"github.com/flpajany/terraform-provider-omni/omniapi"
+)
+
+var userRoles = []string{"None", "Reader", "Operator", "Admin"}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OmniUserResource{}
+var _ resource.ResourceWithImportState = &OmniUserResource{}
+var _ resource.ResourceWithValidateConfig = &OmniUserResource{}
+
+func NewOmniUserResource() resource.Resource {
+	return &OmniUserResource{}
+}
+
+// OmniUserResource defines the resource implementation.
+type OmniUserResource struct {
+	client *omniapi.OmniClient
+}
+
+// OmniUserResourceModel describes the resource data model.
+type OmniUserResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Email      types.String `tfsdk:"email"`
+	Role       types.String `tfsdk:"role"`
+	SAMLLabels types.Map    `tfsdk:"saml_labels"`
+	UserID     types.String `tfsdk:"user_id"`
+}
+
+func (r *OmniUserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *OmniUserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "omni_user resource. Manages the Identity of an email and the User holding its role, import by email",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "User email",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "Email of the user",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "Role of the user: `None`, `Reader`, `Operator` or `Admin`",
+				Required:            true,
+			},
+			"saml_labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "SAML attributes of the identity, matched by the SAML label rules. Keys are given without the `" +
+					auth.SAMLLabelPrefix + "` prefix",
+				Optional: true,
+			},
+			"user_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the User resource",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *OmniUserResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*omniapi.OmniClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *omniapi.OmniClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OmniUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OmniUserResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	samlLabels, diags := userSAMLLabels(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	user, err := r.client.CreateUser(data.Email.ValueString(), data.Role.ValueString(), samlLabels)
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to create user, got error: %s", err))
+		return
+	}
+
+	data.ID = data.Email
+	data.UserID = types.StringValue(user.UserID)
+
+	tflog.Trace(ctx, "create a resource omni_user")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniUserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OmniUserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	user, err := r.client.GetUser(data.ID.ValueString())
+	if omniapi.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to read user, got error: %s", err))
+		return
+	}
+
+	data.Email = types.StringValue(user.Email)
+	data.Role = types.StringValue(user.Role)
+	data.UserID = types.StringValue(user.UserID)
+
+	if len(user.SAMLLabels) > 0 || !data.SAMLLabels.IsNull() {
+		var diags diag.Diagnostics
+		data.SAMLLabels, diags = types.MapValueFrom(ctx, types.StringType, user.SAMLLabels)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniUserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OmniUserResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	samlLabels, diags := userSAMLLabels(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.UpdateUser(data.Email.ValueString(), data.Role.ValueString(), samlLabels); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to update user, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OmniUserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OmniUserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteUser(data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("client Error", fmt.Sprintf("unable to delete user, got error: %s", err))
+		return
+	}
+}
+
+func (r *OmniUserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OmniUserResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data OmniUserResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateUserEmail(data.Email)...)
+
+	if !data.Role.IsNull() && !data.Role.IsUnknown() && !slices.Contains(userRoles, data.Role.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("role"), "Invalid Attribute Value",
+			fmt.Sprintf("role must be one of %s, got %q", strings.Join(userRoles, ", "), data.Role.ValueString()))
+	}
+
+	for key := range data.SAMLLabels.Elements() {
+		if strings.HasPrefix(key, auth.SAMLLabelPrefix) {
+			resp.Diagnostics.AddAttributeError(path.Root("saml_labels").AtMapKey(key), "Invalid Attribute Value",
+				fmt.Sprintf("label %q must be given without the %s prefix", key, auth.SAMLLabelPrefix))
+		}
+	}
+}
+
+// validateUserEmail checks that the email is a bare address in lowercase, the form Omni names identities after.
+func validateUserEmail(email types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if email.IsNull() || email.IsUnknown() {
+		return diags
+	}
+
+	value := email.ValueString()
+	if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+		diags.AddAttributeError(path.Root("email"), "Invalid Attribute Value", fmt.Sprintf("%q is not a valid email address", value))
+		return diags
+	}
+
+	if lower := strings.ToLower(value); lower != value {
+		diags.AddAttributeError(path.Root("email"), "Invalid Attribute Value",
+			fmt.Sprintf("email must be lowercase as Omni identities are, use %q", lower))
+	}
+
+	return diags
+}
+
+func userSAMLLabels(ctx context.Context, data OmniUserResourceModel) (map[string]string, diag.Diagnostics) {
+	labels := map[string]string{}
+
+	if data.SAMLLabels.IsNull() {
+		return labels, nil
+	}
+
+	diags := data.SAMLLabels.ElementsAs(ctx, &labels, false)
+
+	return labels, diags
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOmniUserResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Validation testing
+			{
+				Config:      testAccOmniUserResourceConfig("Owner"),
+				ExpectError: regexp.MustCompile("role must be one of"),
+			},
+			// Create and Read testing
+			{
+				Config: testAccOmniUserResourceConfig("Reader"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_user.test", "id", "terraform-acc-test@example.com"),
+					resource.TestCheckResourceAttr("omni_user.test", "role", "Reader"),
+					resource.TestCheckResourceAttr("omni_user.test", "saml_labels.groups", "platform"),
+					resource.TestCheckResourceAttrSet("omni_user.test", "user_id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "omni_user.test",
+				ImportState:       true,
+				ImportStateId:     "terraform-acc-test@example.com",
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccOmniUserResourceConfig("Operator"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("omni_user.test", "role", "Operator"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccOmniUserResourceConfig(role string) string {
+	return fmt.Sprintf(`
+resource "omni_user" "test" {
+  email = "terraform-acc-test@example.com"
+  role  = %q
+  saml_labels = {
+    groups = "platform"
+  }
+}
+`, role)
+}
+
+func TestValidateUserEmail(t *testing.T) {
+	for email, valid := range map[string]bool{
+		"jane.doe@example.com":        true,
+		"Jane.Doe@example.com":        false,
+		"Jane Doe <jane@example.com>": false,
+		"not-an-email":                false,
+	} {
+		if diags := validateUserEmail(types.StringValue(email)); diags.HasError() == valid {
+			t.Errorf("email %q: expected valid %t, got %v", email, valid, diags)
+		}
+	}
+}
@@ -28,6 +28,9 @@ import (
 	"github.com/siderolabs/omni/client/pkg/template/operations"
 
 	api_management "github.com/siderolabs/omni/client/api/omni/management"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
 )
 
 type OmniClient struct {
@@ -86,7 +89,7 @@ func (o *OmniClient) GetClusters() (safe.List[*typed.Resource[protobuf.ResourceS
 
 func (o *OmniClient) SyncCluster(input io.Reader) error {
 	ctx := o.context
-	st := o.state
+	st := templateState{o.state}
 
 	err := operations.SyncTemplate(ctx, input, io.Discard, st, operations.SyncOptions{})
 	if err != nil {
@@ -96,14 +99,15 @@ func (o *OmniClient) SyncCluster(input io.Reader) error {
 	return nil
 }
 
-func (o *OmniClient) SyncClusterAndWaitForReady(input io.Reader) error {
-	ctx := o.context
-	st := o.state
+// SyncClusterAndWaitForReady syncs a cluster template and waits until the control plane of the
+// cluster is ready, or until timeout.
+func (o *OmniClient) SyncClusterAndWaitForReady(input io.Reader, timeout time.Duration) error {
+	st := templateState{o.state}
 
 	buf := &bytes.Buffer{}
 	tee := io.TeeReader(input, buf)
 
-	err := operations.SyncTemplate(ctx, tee, io.Discard, st, operations.SyncOptions{})
+	err := operations.SyncTemplate(o.context, tee, io.Discard, st, operations.SyncOptions{})
 	if err != nil {
 		return err
 	}
@@ -118,20 +122,39 @@ func (o *OmniClient) SyncClusterAndWaitForReady(input io.Reader) error {
 		return fmt.Errorf("t.ClusterName() : %v", err)
 	}
 
-	// Waiting for the cluster to be Ready
-	for {
-		cluster, err := safe.StateGetByID[*omni.ClusterStatus](ctx, st, name)
-		if err != nil {
-			return err
-		}
-		if cluster.TypedSpec().Value.Ready {
-			break
-		}
+	ctx, cancel := context.WithTimeout(o.context, timeout)
+	defer cancel()
+
+	_, err = o.state.WatchFor(ctx, omni.NewMachineSetStatus(resources.DefaultNamespace, omni.ControlPlanesResourceID(name)).Metadata(),
+		state.WithEventTypes(state.Created, state.Updated),
+		state.WithCondition(func(r resource.Resource) (bool, error) {
+			status, ok := r.(*omni.MachineSetStatus)
+			if !ok {
+				return false, fmt.Errorf("unexpected resource type %T", r)
+			}
+
+			return status.TypedSpec().Value.Ready, nil
+		}))
+	if err != nil {
+		return fmt.Errorf("waiting for the control plane of cluster %s to be ready: %w", name, err)
 	}
 
 	return nil
 }
 
+// GetClusterBootstrapSpec returns the etcd backup the control plane of a cluster was bootstrapped from,
+// empty when it was not restored from a backup.
+func (o *OmniClient) GetClusterBootstrapSpec(cluster string) (clusterUUID, snapshot string, err error) {
+	ms, err := safe.StateGetByID[*omni.MachineSet](o.context, o.state, omni.ControlPlanesResourceID(cluster))
+	if err != nil {
+		return "", "", err
+	}
+
+	spec := ms.TypedSpec().Value.GetBootstrapSpec()
+
+	return spec.GetClusterUuid(), spec.GetSnapshot(), nil
+}
+
 func (o *OmniClient) DeleteCluster(name string) error {
 	ctx := o.context
 	st := o.state
@@ -262,7 +285,7 @@ func (o *OmniClient) GetTalosconfigWithRawTalosconfig(cluster string) (string, e
 func (o *OmniClient) GetTemplateFromClusterName(cluster string) (string, error) {
 	buf := &bytes.Buffer{}
 
-	_, err := operations.ExportTemplate(o.context, o.state, cluster, buf)
+	_, err := operations.ExportTemplate(o.context, templateState{o.state}, cluster, buf)
 	if err != nil {
 		return "", err
 	}
@@ -270,27 +293,64 @@ func (o *OmniClient) GetTemplateFromClusterName(cluster string) (string, error)
 	return buf.String(), nil
 }
 
-func (o *OmniClient) SyncManifests(cluster string) error {
-	ctx := o.context
-	return o.omniClient.Management().WithCluster(cluster).KubernetesSyncManifests(ctx, false,
+// ManifestSyncResult is one response received while syncing Kubernetes manifests.
+type ManifestSyncResult struct {
+	Path    string
+	Skipped bool
+	Diff    string
+	Rollout bool
+}
+
+// SyncManifests syncs the Kubernetes bootstrap manifests of a cluster and returns every response received.
+// With dryRun, only the pending diffs are computed. When timeout is set, it bounds the wait for the rollouts
+// which follows the manifests update, not the update itself. Results collected before a failure
+// (e.g. a rollout error) are returned along with the error.
+func (o *OmniClient) SyncManifests(cluster string, dryRun bool, timeout time.Duration) ([]ManifestSyncResult, error) {
+	ctx, cancel := context.WithCancelCause(o.context)
+	defer cancel(nil)
+
+	var rolloutTimer *time.Timer
+	defer func() {
+		if rolloutTimer != nil {
+			rolloutTimer.Stop()
+		}
+	}()
+
+	var results []ManifestSyncResult
+
+	err := o.omniClient.Management().WithCluster(cluster).KubernetesSyncManifests(ctx, dryRun,
 		func(resp *api_management.KubernetesSyncManifestResponse) error {
 			switch resp.ResponseType {
 			case api_management.KubernetesSyncManifestResponse_UNKNOWN:
 			case api_management.KubernetesSyncManifestResponse_MANIFEST:
 				log.Printf("[INFO] > processing manifest %s\n", resp.Path)
 
-				switch {
-				case resp.Skipped:
-					log.Println("[INFO] < no changes")
-				default:
-					log.Println(resp.Diff)
-					log.Println("[INFO] < applied successfully")
-				}
+				results = append(results, ManifestSyncResult{
+					Path:    resp.Path,
+					Skipped: resp.Skipped,
+					Diff:    resp.Diff,
+				})
 			case api_management.KubernetesSyncManifestResponse_ROLLOUT:
 				log.Printf("[INFO] > waiting for %s\n", resp.Path)
+
+				if timeout > 0 && rolloutTimer == nil {
+					rolloutTimer = time.AfterFunc(timeout, func() {
+						cancel(fmt.Errorf("rollouts not completed after %s", timeout))
+					})
+				}
+
+				results = append(results, ManifestSyncResult{
+					Path:    resp.Path,
+					Rollout: true,
+				})
 			}
 			return nil
 		})
+	if cause := context.Cause(ctx); err != nil && cause != nil {
+		err = cause
+	}
+
+	return results, err
 }
 
 func (o *OmniClient) DeleteClusterMachines(machines safe.List[*typed.Resource[protobuf.ResourceSpec[specs.MachineStatusSpec, *specs.MachineStatusSpec], omni.MachineStatusExtension]]) error {
@@ -316,3 +376,121 @@ func (o *OmniClient) DeleteClusterMachines(machines safe.List[*typed.Resource[pr
 
 	return nil
 }
+
+func (o *OmniClient) GetClusterStatus(cluster string) (*omni.ClusterStatus, error) {
+	return safe.StateGetByID[*omni.ClusterStatus](o.context, o.state, cluster)
+}
+
+func (o *OmniClient) GetClusterUUID(cluster string) (string, error) {
+	u, err := safe.StateGetByID[*omni.ClusterUUID](o.context, o.state, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	return u.TypedSpec().Value.Uuid, nil
+}
+
+func (o *OmniClient) GetClusterMachineSetStatuses(cluster string) (safe.List[*omni.MachineSetStatus], error) {
+	return safe.StateListAll[*omni.MachineSetStatus](o.context, o.state, state.WithLabelQuery(resource.LabelEqual(omni.LabelCluster, cluster)))
+}
+
+func (o *OmniClient) GetTalosUpgradeStatus(cluster string) (*omni.TalosUpgradeStatus, error) {
+	return safe.StateGetByID[*omni.TalosUpgradeStatus](o.context, o.state, cluster)
+}
+
+func (o *OmniClient) GetKubernetesUpgradeStatus(cluster string) (*omni.KubernetesUpgradeStatus, error) {
+	return safe.StateGetByID[*omni.KubernetesUpgradeStatus](o.context, o.state, cluster)
+}
+
+// GetKubernetesAPIURL returns the Kubernetes API server URL users reach the cluster at, out of its kubeconfig.
+func (o *OmniClient) GetKubernetesAPIURL(cluster string) (string, error) {
+	kubeconfig, err := o.GetKubeconfig(cluster)
+	if status.Code(err) == codes.NotFound {
+		return "", fmt.Errorf("kubeconfig of cluster %s: %w", cluster, errNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return kubeconfigServer(kubeconfig)
+}
+
+// kubeconfigServer returns the server of the first cluster of a kubeconfig.
+func kubeconfigServer(kubeconfig string) (string, error) {
+	var config struct {
+		Clusters []struct {
+			Cluster struct {
+				Server string `yaml:"server"`
+			} `yaml:"cluster"`
+		} `yaml:"clusters"`
+	}
+
+	if err := yaml.Unmarshal([]byte(kubeconfig), &config); err != nil {
+		return "", fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+
+	if len(config.Clusters) == 0 || config.Clusters[0].Cluster.Server == "" {
+		return "", fmt.Errorf("no server in kubeconfig")
+	}
+
+	return config.Clusters[0].Cluster.Server, nil
+}
+
+// MachineAllocation tells whether a machine is registered in Omni, whether it is connected and which cluster it belongs to.
+type MachineAllocation struct {
+	Registered bool
+	Connected  bool
+	Cluster    string
+}
+
+// GetMachineAllocations looks up the given machine UUIDs in MachineStatus, and in Machine for their connection.
+func (o *OmniClient) GetMachineAllocations(uuids []string) (map[string]MachineAllocation, error) {
+	machines, err := o.GetMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := make(map[string]MachineAllocation, len(uuids))
+	for _, uuid := range uuids {
+		allocations[uuid] = MachineAllocation{}
+	}
+
+	machines.ForEach(func(r *omni.MachineStatus) {
+		if _, ok := allocations[r.Metadata().ID()]; !ok {
+			return
+		}
+
+		cluster, _ := r.Metadata().Labels().Get(omni.LabelCluster)
+		allocations[r.Metadata().ID()] = MachineAllocation{
+			Registered: true,
+			Cluster:    cluster,
+		}
+	})
+
+	for uuid, allocation := range allocations {
+		if !allocation.Registered {
+			continue
+		}
+
+		machine, err := safe.StateGetByID[*omni.Machine](o.context, o.state, uuid)
+		if state.IsNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		allocation.Connected = machine.TypedSpec().Value.Connected
+		allocations[uuid] = allocation
+	}
+
+	return allocations, nil
+}
+
+func (o *OmniClient) GetCluster(cluster string) (*omni.Cluster, error) {
+	return safe.StateGetByID[*omni.Cluster](o.context, o.state, cluster)
+}
+
+func (o *OmniClient) GetEtcdBackupStoreStatus() (*omni.EtcdBackupStoreStatus, error) {
+	return safe.StateGet[*omni.EtcdBackupStoreStatus](o.context, o.state, omni.NewEtcdBackupStoreStatus().Metadata())
+}
@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/gen/pair"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/constants"
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+const clusterMachineManagedBy = "omni_cluster_machine"
+
+// ClusterMachine is a single machine attached to a machine set out of the cluster template,
+// with the patches applied to this machine only.
+type ClusterMachine struct {
+	Machine    string
+	MachineSet string
+	Cluster    string
+	Patches    []Patch
+}
+
+// clusterMachinePatchID keys a patch by its name, so that reordering the patches does not recreate them.
+func clusterMachinePatchID(machine string, name string) string {
+	return fmt.Sprintf("%03d-%s-%s", constants.PatchBaseWeightClusterMachine, machine, name)
+}
+
+// ApplyClusterMachine attaches a machine to its machine set and applies its patches. Patches
+// previously created for the machine and not listed anymore are destroyed. A machine already
+// attached to a machine set by something else, such as a cluster template, is an error.
+func (o *OmniClient) ApplyClusterMachine(cm ClusterMachine) (*ClusterMachine, error) {
+	machineSet, err := safe.StateGetByID[*omni.MachineSet](o.context, o.state, cm.MachineSet)
+	if err != nil {
+		return nil, fmt.Errorf("machine set %s: %w", cm.MachineSet, err)
+	}
+
+	var ok bool
+	if cm.Cluster, ok = machineSet.Metadata().Labels().Get(omni.LabelCluster); !ok {
+		return nil, fmt.Errorf("machine set %s has no cluster label", cm.MachineSet)
+	}
+
+	existing, err := safe.StateGetByID[*omni.MachineSetNode](o.context, o.state, cm.Machine)
+	if err != nil && !state.IsNotFoundError(err) {
+		return nil, err
+	}
+	if err == nil {
+		if managedBy, _ := existing.Metadata().Labels().Get(ManagedByLabel); managedBy != clusterMachineManagedBy {
+			owner, _ := existing.Metadata().Labels().Get(omni.LabelMachineSet)
+			return nil, fmt.Errorf("machine %s already belongs to machine set %s and is not managed by %s", cm.Machine, owner, clusterMachineManagedBy)
+		}
+	}
+
+	patches := make(map[resource.ID]*omni.ConfigPatch, len(cm.Patches))
+	for _, p := range cm.Patches {
+		patch := omni.NewConfigPatch(resources.DefaultNamespace, clusterMachinePatchID(cm.Machine, p.Name),
+			pair.MakePair(omni.LabelCluster, cm.Cluster),
+			pair.MakePair(omni.LabelClusterMachine, cm.Machine),
+		)
+		patch.Metadata().Annotations().Set("name", p.Name)
+
+		if err := patch.TypedSpec().Value.SetUncompressedData([]byte(p.Data)); err != nil {
+			return nil, err
+		}
+
+		patches[patch.Metadata().ID()] = patch
+	}
+
+	// Patches go first so that the machine joins with its final configuration.
+	for _, p := range cm.Patches {
+		if err := o.applyResource(patches[clusterMachinePatchID(cm.Machine, p.Name)], clusterMachineManagedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	node := omni.NewMachineSetNode(resources.DefaultNamespace, cm.Machine, machineSet)
+	if err := o.applyResource(node, clusterMachineManagedBy); err != nil {
+		return nil, err
+	}
+
+	currentPatches, err := o.clusterMachinePatches(cm.Machine)
+	if err != nil {
+		return nil, err
+	}
+
+	var stalePatches []resource.Pointer
+	currentPatches.ForEach(func(r *omni.ConfigPatch) {
+		if _, ok := patches[r.Metadata().ID()]; !ok {
+			stalePatches = append(stalePatches, r.Metadata())
+		}
+	})
+
+	return &cm, o.destroyResources(stalePatches, 10*time.Minute)
+}
+
+// clusterMachinePatches lists the patches created along with a cluster machine.
+func (o *OmniClient) clusterMachinePatches(machine string) (safe.List[*omni.ConfigPatch], error) {
+	return safe.StateListAll[*omni.ConfigPatch](o.context, o.state, state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelClusterMachine, machine),
+		resource.LabelEqual(ManagedByLabel, clusterMachineManagedBy),
+	))
+}
+
+// GetClusterMachine reads back a cluster machine created with ApplyClusterMachine.
+func (o *OmniClient) GetClusterMachine(machine string) (*ClusterMachine, error) {
+	node, err := safe.StateGetByID[*omni.MachineSetNode](o.context, o.state, machine)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &ClusterMachine{Machine: machine}
+	cm.MachineSet, _ = node.Metadata().Labels().Get(omni.LabelMachineSet)
+	cm.Cluster, _ = node.Metadata().Labels().Get(omni.LabelCluster)
+
+	patches, err := o.clusterMachinePatches(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	err = patches.ForEachErr(func(r *omni.ConfigPatch) error {
+		buf, err := r.TypedSpec().Value.GetUncompressedData()
+		if err != nil {
+			return err
+		}
+		defer buf.Free()
+
+		name, _ := r.Metadata().Annotations().Get("name")
+		cm.Patches = append(cm.Patches, Patch{Name: name, Data: string(buf.Data())})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cm, nil
+}
+
+// DeleteClusterMachine removes a machine from its machine set, waits for Omni to tear it down
+// (etcd membership, Kubernetes node, machine reset) and destroys its patches.
+func (o *OmniClient) DeleteClusterMachine(machine string, timeout time.Duration) error {
+	node := resource.NewMetadata(resources.DefaultNamespace, omni.MachineSetNodeType, machine, resource.VersionUndefined)
+	if err := o.destroyResources([]resource.Pointer{node}, timeout); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(o.context, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		_, err := o.state.Get(ctx, omni.NewClusterMachine(resources.DefaultNamespace, machine).Metadata())
+		if state.IsNotFoundError(err) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for cluster machine %s teardown: %w", machine, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	patches, err := o.clusterMachinePatches(machine)
+	if err != nil {
+		return err
+	}
+
+	var pointers []resource.Pointer
+	patches.ForEach(func(r *omni.ConfigPatch) {
+		pointers = append(pointers, r.Metadata())
+	})
+
+	return o.destroyResources(pointers, 10*time.Minute)
+}
+
+// WaitForClusterMachineReady waits until a cluster machine is running with its configuration applied, or until timeout.
+func (o *OmniClient) WaitForClusterMachineReady(machine string, timeout time.Duration) (*omni.ClusterMachineStatus, error) {
+	ctx, cancel := context.WithTimeout(o.context, timeout)
+	defer cancel()
+
+	r, err := o.state.WatchFor(ctx, omni.NewClusterMachineStatus(resources.DefaultNamespace, machine).Metadata(),
+		state.WithEventTypes(state.Created, state.Updated),
+		state.WithCondition(func(r resource.Resource) (bool, error) {
+			status, ok := r.(*omni.ClusterMachineStatus)
+			if !ok {
+				return false, fmt.Errorf("unexpected resource type %T", r)
+			}
+
+			value := status.TypedSpec().Value
+
+			return value.Ready && value.Stage == specs.ClusterMachineStatusSpec_RUNNING && value.ConfigUpToDate, nil
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("waiting for cluster machine %s to be ready: %w", machine, err)
+	}
+
+	return r.(*omni.ClusterMachineStatus), nil
+}
+
+func (o *OmniClient) GetClusterMachineStatus(machine string) (*omni.ClusterMachineStatus, error) {
+	return safe.StateGetByID[*omni.ClusterMachineStatus](o.context, o.state, machine)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+const configPatchManagedBy = "omni_config_patch"
+
+// ConfigPatch is a config patch managed out of the cluster template. Its target is given by the
+// labels: Cluster alone, Cluster and MachineSet, Cluster and ClusterMachine, or Machine alone.
+type ConfigPatch struct {
+	ID             string
+	Name           string
+	Cluster        string
+	MachineSet     string
+	ClusterMachine string
+	Machine        string
+	Data           string
+
+	// Managed tells whether the patch was created by omni_config_patch, as opposed to a cluster template or the Omni UI.
+	Managed bool
+}
+
+// ApplyConfigPatch creates or updates a config patch.
+func (o *OmniClient) ApplyConfigPatch(p ConfigPatch) error {
+	patch := omni.NewConfigPatch(resources.DefaultNamespace, p.ID)
+	patch.Metadata().Annotations().Set("name", p.Name)
+
+	for label, value := range map[string]string{
+		omni.LabelCluster:        p.Cluster,
+		omni.LabelMachineSet:     p.MachineSet,
+		omni.LabelClusterMachine: p.ClusterMachine,
+		omni.LabelMachine:        p.Machine,
+	} {
+		if value != "" {
+			patch.Metadata().Labels().Set(label, value)
+		}
+	}
+
+	if err := patch.TypedSpec().Value.SetUncompressedData([]byte(p.Data)); err != nil {
+		return err
+	}
+
+	return o.applyResource(patch, configPatchManagedBy)
+}
+
+// GetConfigPatch reads back a config patch.
+func (o *OmniClient) GetConfigPatch(id string) (*ConfigPatch, error) {
+	patch, err := safe.StateGetByID[*omni.ConfigPatch](o.context, o.state, id)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := patch.TypedSpec().Value.GetUncompressedData()
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Free()
+
+	labels := patch.Metadata().Labels()
+	p := &ConfigPatch{
+		ID:   id,
+		Data: string(buf.Data()),
+	}
+	p.Name, _ = patch.Metadata().Annotations().Get("name")
+	p.Cluster, _ = labels.Get(omni.LabelCluster)
+	p.MachineSet, _ = labels.Get(omni.LabelMachineSet)
+	p.ClusterMachine, _ = labels.Get(omni.LabelClusterMachine)
+	p.Machine, _ = labels.Get(omni.LabelMachine)
+
+	managedBy, _ := labels.Get(ManagedByLabel)
+	p.Managed = managedBy == configPatchManagedBy
+
+	return p, nil
+}
+
+// DeleteConfigPatch destroys a config patch.
+func (o *OmniClient) DeleteConfigPatch(id string) error {
+	return o.destroyResources([]resource.Pointer{omni.NewConfigPatch(resources.DefaultNamespace, id).Metadata()}, 10*time.Minute)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+// EtcdBackup is an etcd backup of a cluster stored in the backup store.
+type EtcdBackup struct {
+	ID        string
+	Snapshot  string
+	Size      uint64
+	CreatedAt time.Time
+}
+
+// CreateEtcdManualBackup requests a backup of the etcd of a cluster and waits for it to be stored.
+func (o *OmniClient) CreateEtcdManualBackup(cluster string, timeout time.Duration) (*EtcdBackup, error) {
+	ctx, cancel := context.WithTimeout(o.context, timeout)
+	defer cancel()
+
+	st := o.state
+
+	// Progress is judged against the previous status, the server timestamps can't be compared with the local clock.
+	// Unset timestamps read as the Unix epoch.
+	lastAttempt, lastBackup := time.Unix(0, 0), time.Unix(0, 0)
+
+	previous, err := safe.StateGet[*omni.EtcdBackupStatus](ctx, st, omni.NewEtcdBackupStatus(cluster).Metadata())
+	switch {
+	case err == nil:
+		lastAttempt = previous.TypedSpec().Value.GetLastBackupAttempt().AsTime()
+		lastBackup = previous.TypedSpec().Value.GetLastBackupTime().AsTime()
+	case !state.IsNotFoundError(err):
+		return nil, err
+	}
+
+	requestedAt := time.Now().Truncate(time.Second)
+
+	backup := omni.NewEtcdManualBackup(cluster)
+	backup.TypedSpec().Value.BackupAt = timestamppb.New(requestedAt)
+
+	_, err = st.Get(ctx, backup.Metadata())
+	switch {
+	case state.IsNotFoundError(err):
+		err = st.Create(ctx, backup)
+	case err == nil:
+		_, err = safe.StateUpdateWithConflicts(ctx, st, backup.Metadata(), func(r *omni.EtcdManualBackup) error {
+			r.TypedSpec().Value.BackupAt = timestamppb.New(requestedAt)
+
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = st.WatchFor(ctx, omni.NewEtcdBackupStatus(cluster).Metadata(),
+		state.WithEventTypes(state.Created, state.Updated),
+		state.WithCondition(func(r resource.Resource) (bool, error) {
+			status, ok := r.(*omni.EtcdBackupStatus)
+			if !ok {
+				return false, fmt.Errorf("unexpected resource type %T", r)
+			}
+
+			value := status.TypedSpec().Value
+			if !value.GetLastBackupAttempt().AsTime().After(lastAttempt) {
+				return false, nil
+			}
+
+			switch value.Status {
+			case specs.EtcdBackupStatusSpec_Error:
+				return false, fmt.Errorf("etcd backup of cluster %s failed: %s", cluster, value.Error)
+			case specs.EtcdBackupStatusSpec_Ok:
+				return value.GetLastBackupTime().AsTime().After(lastBackup), nil
+			default:
+				return false, nil
+			}
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("waiting for the etcd backup of cluster %s: %w", cluster, err)
+	}
+
+	backups, err := o.ListEtcdBackups(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *EtcdBackup
+	for _, b := range backups {
+		if b.CreatedAt.After(lastBackup) && (latest == nil || b.CreatedAt.After(latest.CreatedAt)) {
+			latest = b
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("etcd backup of cluster %s is missing from the backup store", cluster)
+	}
+
+	return latest, nil
+}
+
+// ListEtcdBackups lists the etcd backups of a cluster available in the backup store.
+func (o *OmniClient) ListEtcdBackups(cluster string) ([]*EtcdBackup, error) {
+	list, err := safe.StateListAll[*omni.EtcdBackup](o.context, o.state,
+		state.WithLabelQuery(resource.LabelEqual(omni.LabelCluster, cluster)))
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]*EtcdBackup, 0, list.Len())
+	list.ForEach(func(r *omni.EtcdBackup) {
+		spec := r.TypedSpec().Value
+		backups = append(backups, &EtcdBackup{
+			ID:        r.Metadata().ID(),
+			Snapshot:  spec.Snapshot,
+			Size:      spec.Size,
+			CreatedAt: spec.GetCreatedAt().AsTime(),
+		})
+	})
+
+	return backups, nil
+}
+
+// GetEtcdBackup returns an etcd backup of a cluster.
+func (o *OmniClient) GetEtcdBackup(cluster, id string) (*EtcdBackup, error) {
+	backups, err := o.ListEtcdBackups(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range backups {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+
+	return nil, fmt.Errorf("etcd backup %s of cluster %s: %w", id, cluster, errNotFound)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+const etcdBackupS3ConfigurationManagedBy = "omni_etcd_backup_s3_configuration"
+
+// EtcdBackupS3Configuration is the S3 store of the etcd backups, a singleton of Omni.
+type EtcdBackupS3Configuration struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// ApplyEtcdBackupS3Configuration creates or replaces the S3 configuration of the etcd backup store.
+func (o *OmniClient) ApplyEtcdBackupS3Configuration(c EtcdBackupS3Configuration) error {
+	conf := omni.NewEtcdBackupS3Conf()
+
+	spec := conf.TypedSpec().Value
+	spec.Bucket = c.Bucket
+	spec.Region = c.Region
+	spec.Endpoint = c.Endpoint
+	spec.AccessKeyId = c.AccessKeyID
+	spec.SecretAccessKey = c.SecretAccessKey
+	spec.SessionToken = c.SessionToken
+
+	return o.applyResource(conf, etcdBackupS3ConfigurationManagedBy)
+}
+
+// GetEtcdBackupS3Configuration returns the S3 configuration of the etcd backup store.
+func (o *OmniClient) GetEtcdBackupS3Configuration() (*EtcdBackupS3Configuration, error) {
+	conf, err := safe.StateGet[*omni.EtcdBackupS3Conf](o.context, o.state, omni.NewEtcdBackupS3Conf().Metadata())
+	if err != nil {
+		return nil, err
+	}
+
+	spec := conf.TypedSpec().Value
+
+	return &EtcdBackupS3Configuration{
+		Bucket:          spec.Bucket,
+		Region:          spec.Region,
+		Endpoint:        spec.Endpoint,
+		AccessKeyID:     spec.AccessKeyId,
+		SecretAccessKey: spec.SecretAccessKey,
+		SessionToken:    spec.SessionToken,
+	}, nil
+}
+
+// DeleteEtcdBackupS3Configuration removes the S3 configuration, which disables the etcd backup store.
+func (o *OmniClient) DeleteEtcdBackupS3Configuration() error {
+	return o.destroyResources([]resource.Pointer{omni.NewEtcdBackupS3Conf().Metadata()}, 5*time.Minute)
+}
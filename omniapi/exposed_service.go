@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+// ExposedService is a Kubernetes service of a cluster exposed by the Omni workload proxy.
+type ExposedService struct {
+	ID string
+	// Name is the Kubernetes service as name.namespace.
+	Name       string
+	Label      string
+	IconBase64 string
+	Port       uint32
+	URL        string
+	Error      string
+}
+
+// ListExposedServices lists the services of a cluster exposed by the workload proxy.
+func (o *OmniClient) ListExposedServices(cluster string) ([]*ExposedService, error) {
+	list, err := safe.StateListAll[*omni.ExposedService](o.context, o.state,
+		state.WithLabelQuery(resource.LabelEqual(omni.LabelCluster, cluster)))
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]*ExposedService, 0, list.Len())
+	list.ForEach(func(r *omni.ExposedService) {
+		spec := r.TypedSpec().Value
+
+		// IDs are <cluster>/<name>.<namespace>
+		id := r.Metadata().ID()
+		services = append(services, &ExposedService{
+			ID:         id,
+			Name:       strings.TrimPrefix(id, cluster+"/"),
+			Label:      spec.Label,
+			IconBase64: spec.IconBase64,
+			Port:       spec.Port,
+			URL:        spec.Url,
+			Error:      spec.Error,
+		})
+	})
+
+	return services, nil
+}
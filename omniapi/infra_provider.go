@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+
+	"github.com/siderolabs/omni/client/pkg/access"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/infra"
+)
+
+// infraProviderRole is the role Omni gives to the service accounts of infra providers.
+const infraProviderRole = "InfraProvider"
+
+// InfraProviderStatus is what an infra provider reports to Omni once connected.
+type InfraProviderStatus struct {
+	Connected     bool
+	Name          string
+	Description   string
+	LastHeartbeat time.Time
+	Error         string
+}
+
+// InfraProviderServiceAccountName returns the name of the service account of an infra provider.
+func InfraProviderServiceAccountName(providerID string) string {
+	return access.InfraProviderServiceAccountPrefix + providerID
+}
+
+// CreateInfraProviderServiceAccount creates the service account an infra provider connects to Omni with.
+func (o *OmniClient) CreateInfraProviderServiceAccount(providerID string, ttl time.Duration) (*ServiceAccountKey, error) {
+	return o.CreateServiceAccount(InfraProviderServiceAccountName(providerID), infraProviderRole, ttl)
+}
+
+// GetInfraProviderStatus returns the status and the health reported by an infra provider.
+// A provider which never connected has an empty status.
+func (o *OmniClient) GetInfraProviderStatus(providerID string) (*InfraProviderStatus, error) {
+	result := &InfraProviderStatus{}
+
+	status, err := safe.StateGetByID[*infra.ProviderStatus](o.context, o.state, providerID)
+	switch {
+	case state.IsNotFoundError(err):
+	case err != nil:
+		return nil, err
+	default:
+		result.Connected = true
+		result.Name = status.TypedSpec().Value.Name
+		result.Description = status.TypedSpec().Value.Description
+	}
+
+	health, err := safe.StateGetByID[*infra.ProviderHealthStatus](o.context, o.state, providerID)
+	switch {
+	case state.IsNotFoundError(err):
+	case err != nil:
+		return nil, err
+	default:
+		result.LastHeartbeat = health.TypedSpec().Value.GetLastHeartbeatTimestamp().AsTime()
+		result.Error = health.TypedSpec().Value.Error
+	}
+
+	return result, nil
+}
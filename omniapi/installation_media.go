@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+
+	"github.com/siderolabs/omni/client/pkg/constants"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+// InstallationMedia is a boot media Omni can generate.
+type InstallationMedia struct {
+	ID           string
+	Name         string
+	Architecture string
+	Profile      string
+	Filename     string
+	SecureBoot   bool
+}
+
+// ListInstallationMedia lists the boot media Omni can generate.
+func (o *OmniClient) ListInstallationMedia() ([]InstallationMedia, error) {
+	list, err := safe.StateListAll[*omni.InstallationMedia](o.context, o.state)
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]InstallationMedia, 0, list.Len())
+	list.ForEach(func(r *omni.InstallationMedia) {
+		spec := r.TypedSpec().Value
+		media = append(media, InstallationMedia{
+			ID:           r.Metadata().ID(),
+			Name:         spec.Name,
+			Architecture: spec.Architecture,
+			Profile:      spec.Profile,
+			Filename:     spec.DestFilePrefix + "." + spec.Extension,
+			SecureBoot:   !spec.NoSecureBoot,
+		})
+	})
+
+	return media, nil
+}
+
+// FindInstallationMedia looks a boot media up by name or profile and architecture, the way omnictl download does.
+func FindInstallationMedia(media []InstallationMedia, platform, arch string) (*InstallationMedia, error) {
+	var found []InstallationMedia
+
+	for _, m := range media {
+		match := strings.EqualFold(m.Name, platform) || strings.EqualFold(m.Profile, platform)
+		if strings.EqualFold(platform, "iso") {
+			match = strings.Contains(strings.ToLower(m.Name), "iso")
+		}
+
+		if match && m.Architecture == arch {
+			found = append(found, m)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no installation media found for %q on %s", platform, arch)
+	case 1:
+		return &found[0], nil
+	}
+
+	ids := make([]string, 0, len(found))
+	for _, m := range found {
+		ids = append(ids, m.ID)
+	}
+
+	return nil, fmt.Errorf("multiple installation media found for %q on %s: %s", platform, arch, strings.Join(ids, ", "))
+}
+
+// InstallationMediaURL returns the Omni URL generating a boot media out of a schematic.
+// Downloads are authenticated like the other requests to Omni.
+func (o *OmniClient) InstallationMediaURL(schematicID, talosVersion, mediaID string, secureBoot bool) (string, error) {
+	u, err := url.Parse(o.omniClient.Endpoint())
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = "https"
+
+	if u.Path, err = url.JoinPath(u.Path, "image", schematicID, talosVersion, mediaID); err != nil {
+		return "", err
+	}
+
+	if secureBoot {
+		query := u.Query()
+		query.Add(constants.SecureBoot, "true")
+
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
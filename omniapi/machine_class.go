@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+
+	"github.com/siderolabs/omni/client/pkg/cosi/labels"
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+const machineClassManagedBy = "omni_machine_class"
+
+// ParseMachineClassSelectors parses the match labels of a machine class. Each selector is a
+// comma separated list of terms matched together, a machine matches if any selector matches.
+func ParseMachineClassSelectors(selectors []string) (resource.LabelQueries, error) {
+	queries := make(resource.LabelQueries, 0, len(selectors))
+
+	for _, selector := range selectors {
+		query, err := labels.ParseQuery(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, *query)
+	}
+
+	return queries, nil
+}
+
+// ApplyMachineClass creates or updates a machine class matching machines by labels.
+func (o *OmniClient) ApplyMachineClass(name string, matchLabels []string) error {
+	class := omni.NewMachineClass(resources.DefaultNamespace, name)
+	class.TypedSpec().Value.MatchLabels = matchLabels
+
+	return o.applyResource(class, machineClassManagedBy)
+}
+
+// GetMachineClass returns the match labels of a machine class.
+func (o *OmniClient) GetMachineClass(name string) ([]string, error) {
+	class, err := safe.StateGetByID[*omni.MachineClass](o.context, o.state, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return class.TypedSpec().Value.MatchLabels, nil
+}
+
+// DeleteMachineClass destroys a machine class.
+func (o *OmniClient) DeleteMachineClass(name string) error {
+	return o.destroyResources([]resource.Pointer{omni.NewMachineClass(resources.DefaultNamespace, name).Metadata()}, 5*time.Minute)
+}
+
+// GetMatchingMachines returns the UUIDs of the machines whose labels match the selectors.
+func (o *OmniClient) GetMatchingMachines(matchLabels []string) ([]string, error) {
+	queries, err := ParseMachineClassSelectors(matchLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := o.GetMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []string{}
+	machines.ForEach(func(r *omni.MachineStatus) {
+		if queries.Matches(*r.Metadata().Labels()) {
+			matching = append(matching, r.Metadata().ID())
+		}
+	})
+
+	return matching, nil
+}
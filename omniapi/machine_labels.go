@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+func isSystemLabel(key string) bool {
+	return strings.HasPrefix(key, omni.SystemLabelPrefix)
+}
+
+// GetMachineLabels returns the user labels set on a machine through MachineLabels,
+// system labels excluded. A machine without MachineLabels has no user labels.
+func (o *OmniClient) GetMachineLabels(machine string) (map[string]string, error) {
+	labels := map[string]string{}
+
+	ml, err := safe.StateGetByID[*omni.MachineLabels](o.context, o.state, machine)
+	if state.IsNotFoundError(err) {
+		return labels, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range ml.Metadata().Labels().Raw() {
+		if !isSystemLabel(key) {
+			labels[key] = value
+		}
+	}
+
+	return labels, nil
+}
+
+// SetMachineLabels replaces the user labels of a machine. System labels are kept as they are.
+func (o *OmniClient) SetMachineLabels(machine string, labels map[string]string) error {
+	ctx := o.context
+	st := o.state
+
+	ml := omni.NewMachineLabels(resources.DefaultNamespace, machine)
+
+	_, err := st.Get(ctx, ml.Metadata())
+	if state.IsNotFoundError(err) {
+		if len(labels) == 0 {
+			return nil
+		}
+
+		for key, value := range labels {
+			ml.Metadata().Labels().Set(key, value)
+		}
+
+		return st.Create(ctx, ml)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = safe.StateUpdateWithConflicts(ctx, st, ml.Metadata(), func(r *omni.MachineLabels) error {
+		for _, key := range r.Metadata().Labels().Keys() {
+			if !isSystemLabel(key) {
+				r.Metadata().Labels().Delete(key)
+			}
+		}
+
+		for key, value := range labels {
+			r.Metadata().Labels().Set(key, value)
+		}
+
+		return nil
+	})
+
+	return err
+}
@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/infra"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+const machineRequestSetManagedBy = "omni_machine_request_set"
+
+// MachineRequestSet asks an infra provider for machines, the schematic of the machines is built
+// out of Extensions, KernelArgs and MetaValues.
+type MachineRequestSet struct {
+	ProviderID   string
+	MachineCount int
+	TalosVersion string
+	Extensions   []string
+	KernelArgs   []string
+	MetaValues   map[uint32]string
+	ProviderData string
+}
+
+// ApplyMachineRequestSet creates or updates a machine request set.
+func (o *OmniClient) ApplyMachineRequestSet(name string, s MachineRequestSet) error {
+	set := omni.NewMachineRequestSet(resources.DefaultNamespace, name)
+
+	spec := set.TypedSpec().Value
+	spec.ProviderId = s.ProviderID
+	spec.MachineCount = int32(s.MachineCount)
+	spec.TalosVersion = s.TalosVersion
+	spec.Extensions = s.Extensions
+	spec.KernelArgs = s.KernelArgs
+	spec.ProviderData = s.ProviderData
+
+	keys := make([]uint32, 0, len(s.MetaValues))
+	for k := range s.MetaValues {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		spec.MetaValues = append(spec.MetaValues, &specs.MetaValue{Key: k, Value: s.MetaValues[k]})
+	}
+
+	return o.applyResource(set, machineRequestSetManagedBy)
+}
+
+// GetMachineRequestSet returns a machine request set.
+func (o *OmniClient) GetMachineRequestSet(name string) (*MachineRequestSet, error) {
+	set, err := safe.StateGetByID[*omni.MachineRequestSet](o.context, o.state, name)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := set.TypedSpec().Value
+
+	s := &MachineRequestSet{
+		ProviderID:   spec.ProviderId,
+		MachineCount: int(spec.MachineCount),
+		TalosVersion: spec.TalosVersion,
+		Extensions:   spec.Extensions,
+		KernelArgs:   spec.KernelArgs,
+		MetaValues:   map[uint32]string{},
+		ProviderData: spec.ProviderData,
+	}
+
+	for _, m := range spec.MetaValues {
+		s.MetaValues[m.Key] = m.Value
+	}
+
+	return s, nil
+}
+
+// DeleteMachineRequestSet destroys a machine request set, the infra provider deprovisions its machines.
+func (o *OmniClient) DeleteMachineRequestSet(name string, timeout time.Duration) error {
+	return o.destroyResources([]resource.Pointer{omni.NewMachineRequestSet(resources.DefaultNamespace, name).Metadata()}, timeout)
+}
+
+// GetMachineRequestSetMachines returns the UUIDs of the machines provisioned for a machine request set,
+// sorted. Machines which are not provisioned yet are left out.
+func (o *OmniClient) GetMachineRequestSetMachines(name string) ([]string, error) {
+	machines, _, err := o.machineRequestSetMachines(o.context, name)
+
+	return machines, err
+}
+
+// WaitMachineRequestSetMachines waits for the count machines of a machine request set to be provisioned and
+// connected to Omni, and returns their UUIDs sorted.
+func (o *OmniClient) WaitMachineRequestSetMachines(name string, count int, timeout time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(o.context, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		machines, pending, err := o.machineRequestSetMachines(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(machines) == count && pending == 0 {
+			connected, err := o.machinesConnected(ctx, machines)
+			if err != nil {
+				return nil, err
+			}
+
+			if connected {
+				return machines, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for the machines of machine request set %s: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// machineRequestSetMachines returns the machines provisioned for the requests of a machine request set
+// and the number of requests still being provisioned. A failed request is an error.
+func (o *OmniClient) machineRequestSetMachines(ctx context.Context, name string) ([]string, int, error) {
+	requests, err := safe.StateListAll[*infra.MachineRequest](ctx, o.state,
+		state.WithLabelQuery(resource.LabelEqual(omni.LabelMachineRequestSet, name)))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	machines := []string{}
+	pending := 0
+
+	err = requests.ForEachErr(func(request *infra.MachineRequest) error {
+		status, err := safe.StateGetByID[*infra.MachineRequestStatus](ctx, o.state, request.Metadata().ID())
+		if state.IsNotFoundError(err) {
+			pending++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		spec := status.TypedSpec().Value
+
+		switch {
+		case spec.Stage == specs.MachineRequestStatusSpec_FAILED:
+			return fmt.Errorf("machine request %s failed: %s", request.Metadata().ID(), spec.Error)
+		case spec.Stage == specs.MachineRequestStatusSpec_PROVISIONED && spec.Id != "":
+			machines = append(machines, spec.Id)
+		default:
+			pending++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	slices.Sort(machines)
+
+	return machines, pending, nil
+}
+
+// machinesConnected tells whether every machine is registered and connected to Omni.
+func (o *OmniClient) machinesConnected(ctx context.Context, machines []string) (bool, error) {
+	for _, id := range machines {
+		machine, err := safe.StateGetByID[*omni.Machine](ctx, o.state, id)
+		if state.IsNotFoundError(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if !machine.TypedSpec().Value.Connected {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,337 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/siderolabs/gen/pair"
+
+	"github.com/siderolabs/omni/client/api/omni/specs"
+	"github.com/siderolabs/omni/client/pkg/constants"
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+const machineSetManagedBy = "omni_machine_set"
+
+// MachineSetStrategy is the update or delete strategy of a machine set.
+type MachineSetStrategy struct {
+	Rolling        bool
+	MaxParallelism uint32
+}
+
+// Patch is a named config patch.
+type Patch struct {
+	Name string
+	Data string
+}
+
+// MachineSet is a machine set managed out of the cluster template. Machines are either listed
+// explicitly or allocated from MachineClass.
+type MachineSet struct {
+	ID             string
+	Cluster        string
+	ControlPlane   bool
+	Machines       []string
+	MachineClass   string
+	MachineCount   uint32
+	UpdateStrategy MachineSetStrategy
+	DeleteStrategy MachineSetStrategy
+	Patches        []Patch
+}
+
+func strategyType(s MachineSetStrategy) specs.MachineSetSpec_UpdateStrategy {
+	if s.Rolling {
+		return specs.MachineSetSpec_Rolling
+	}
+
+	return specs.MachineSetSpec_Unset
+}
+
+func strategyConfig(s MachineSetStrategy) *specs.MachineSetSpec_UpdateStrategyConfig {
+	if !s.Rolling || s.MaxParallelism == 0 {
+		return nil
+	}
+
+	return &specs.MachineSetSpec_UpdateStrategyConfig{
+		Rolling: &specs.MachineSetSpec_RollingUpdateStrategyConfig{
+			MaxParallelism: s.MaxParallelism,
+		},
+	}
+}
+
+func strategyFrom(t specs.MachineSetSpec_UpdateStrategy, config *specs.MachineSetSpec_UpdateStrategyConfig) MachineSetStrategy {
+	return MachineSetStrategy{
+		Rolling:        t == specs.MachineSetSpec_Rolling,
+		MaxParallelism: config.GetRolling().GetMaxParallelism(),
+	}
+}
+
+// machineSetPatchID keys a patch by its name, so that reordering the patches does not recreate them.
+// Omni applies patches of the same weight in ID order, that is in name order.
+func machineSetPatchID(machineSet string, name string) string {
+	return fmt.Sprintf("%03d-%s-%s", constants.PatchBaseWeightMachineSet, machineSet, name)
+}
+
+// ApplyMachineSet creates or updates a machine set along with its machine set nodes and patches.
+// Nodes and patches previously created for the machine set and not listed anymore are destroyed.
+func (o *OmniClient) ApplyMachineSet(ms MachineSet) error {
+	existing, err := safe.StateGetByID[*omni.MachineSet](o.context, o.state, ms.ID)
+	if err != nil && !state.IsNotFoundError(err) {
+		return err
+	}
+	if err == nil {
+		if managedBy, _ := existing.Metadata().Labels().Get(ManagedByLabel); managedBy != machineSetManagedBy {
+			return fmt.Errorf("machine set %s already exists and is not managed by %s", ms.ID, machineSetManagedBy)
+		}
+	}
+
+	machineSet := omni.NewMachineSet(resources.DefaultNamespace, ms.ID)
+	machineSet.Metadata().Labels().Set(omni.LabelCluster, ms.Cluster)
+	if ms.ControlPlane {
+		machineSet.Metadata().Labels().Set(omni.LabelControlPlaneRole, "")
+	} else {
+		machineSet.Metadata().Labels().Set(omni.LabelWorkerRole, "")
+	}
+
+	spec := machineSet.TypedSpec().Value
+	spec.UpdateStrategy = strategyType(ms.UpdateStrategy)
+	spec.UpdateStrategyConfig = strategyConfig(ms.UpdateStrategy)
+	spec.DeleteStrategy = strategyType(ms.DeleteStrategy)
+	spec.DeleteStrategyConfig = strategyConfig(ms.DeleteStrategy)
+
+	if ms.MachineClass != "" {
+		spec.MachineAllocation = &specs.MachineSetSpec_MachineAllocation{
+			Name:         ms.MachineClass,
+			MachineCount: ms.MachineCount,
+			Source:       specs.MachineSetSpec_MachineAllocation_MachineClass,
+		}
+	}
+
+	nodes := make(map[resource.ID]*omni.MachineSetNode, len(ms.Machines))
+	for _, machine := range ms.Machines {
+		nodes[machine] = omni.NewMachineSetNode(resources.DefaultNamespace, machine, machineSet)
+	}
+
+	patches := make(map[resource.ID]*omni.ConfigPatch, len(ms.Patches))
+	for _, p := range ms.Patches {
+		patch := omni.NewConfigPatch(resources.DefaultNamespace, machineSetPatchID(ms.ID, p.Name),
+			pair.MakePair(omni.LabelCluster, ms.Cluster),
+			pair.MakePair(omni.LabelMachineSet, ms.ID),
+		)
+		patch.Metadata().Annotations().Set("name", p.Name)
+
+		if err := patch.TypedSpec().Value.SetUncompressedData([]byte(p.Data)); err != nil {
+			return err
+		}
+
+		patches[patch.Metadata().ID()] = patch
+	}
+
+	currentNodes, currentPatches, err := o.machineSetChildren(ms.ID)
+	if err != nil {
+		return err
+	}
+
+	// Stale nodes go first: a machine set can't switch to a machine class while it still has nodes.
+	var staleNodes []resource.Pointer
+	currentNodes.ForEach(func(r *omni.MachineSetNode) {
+		if _, ok := nodes[r.Metadata().ID()]; !ok {
+			staleNodes = append(staleNodes, r.Metadata())
+		}
+	})
+	if err := o.destroyResources(staleNodes, 10*time.Minute); err != nil {
+		return err
+	}
+
+	if err := o.applyResource(machineSet, machineSetManagedBy); err != nil {
+		return err
+	}
+
+	for _, machine := range ms.Machines {
+		if err := o.applyResource(nodes[machine], machineSetManagedBy); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range ms.Patches {
+		if err := o.applyResource(patches[machineSetPatchID(ms.ID, p.Name)], machineSetManagedBy); err != nil {
+			return err
+		}
+	}
+
+	var stalePatches []resource.Pointer
+	currentPatches.ForEach(func(r *omni.ConfigPatch) {
+		if _, ok := patches[r.Metadata().ID()]; !ok {
+			stalePatches = append(stalePatches, r.Metadata())
+		}
+	})
+
+	return o.destroyResources(stalePatches, 10*time.Minute)
+}
+
+// machineSetChildren lists the machine set nodes and patches created along with a machine set.
+func (o *OmniClient) machineSetChildren(id string) (safe.List[*omni.MachineSetNode], safe.List[*omni.ConfigPatch], error) {
+	query := state.WithLabelQuery(
+		resource.LabelEqual(omni.LabelMachineSet, id),
+		resource.LabelEqual(ManagedByLabel, machineSetManagedBy),
+	)
+
+	nodes, err := safe.StateListAll[*omni.MachineSetNode](o.context, o.state, query)
+	if err != nil {
+		return nodes, safe.List[*omni.ConfigPatch]{}, err
+	}
+
+	patches, err := safe.StateListAll[*omni.ConfigPatch](o.context, o.state, query)
+
+	return nodes, patches, err
+}
+
+// GetMachineSet reads back a machine set created with ApplyMachineSet.
+func (o *OmniClient) GetMachineSet(id string) (*MachineSet, error) {
+	machineSet, err := safe.StateGetByID[*omni.MachineSet](o.context, o.state, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := machineSet.TypedSpec().Value
+	cluster, _ := machineSet.Metadata().Labels().Get(omni.LabelCluster)
+	_, controlPlane := machineSet.Metadata().Labels().Get(omni.LabelControlPlaneRole)
+
+	ms := &MachineSet{
+		ID:             id,
+		Cluster:        cluster,
+		ControlPlane:   controlPlane,
+		UpdateStrategy: strategyFrom(spec.UpdateStrategy, spec.UpdateStrategyConfig),
+		DeleteStrategy: strategyFrom(spec.DeleteStrategy, spec.DeleteStrategyConfig),
+	}
+
+	if spec.MachineAllocation != nil {
+		ms.MachineClass = spec.MachineAllocation.Name
+		ms.MachineCount = spec.MachineAllocation.MachineCount
+	}
+
+	nodes, patches, err := o.machineSetChildren(id)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes.ForEach(func(r *omni.MachineSetNode) {
+		ms.Machines = append(ms.Machines, r.Metadata().ID())
+	})
+
+	err = patches.ForEachErr(func(r *omni.ConfigPatch) error {
+		buf, err := r.TypedSpec().Value.GetUncompressedData()
+		if err != nil {
+			return err
+		}
+		defer buf.Free()
+
+		name, _ := r.Metadata().Annotations().Get("name")
+		ms.Patches = append(ms.Patches, Patch{Name: name, Data: string(buf.Data())})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
+
+// DeleteMachineSet destroys a machine set created with ApplyMachineSet, then its patches.
+func (o *OmniClient) DeleteMachineSet(id string) error {
+	nodes, patches, err := o.machineSetChildren(id)
+	if err != nil {
+		return err
+	}
+
+	pointers := []resource.Pointer{omni.NewMachineSet(resources.DefaultNamespace, id).Metadata()}
+	nodes.ForEach(func(r *omni.MachineSetNode) {
+		pointers = append(pointers, r.Metadata())
+	})
+
+	if err := o.destroyResources(pointers, 30*time.Minute); err != nil {
+		return err
+	}
+
+	pointers = nil
+	patches.ForEach(func(r *omni.ConfigPatch) {
+		pointers = append(pointers, r.Metadata())
+	})
+
+	return o.destroyResources(pointers, 10*time.Minute)
+}
+
+// WaitForMachineSetReady waits until the status of a machine set is ready with the requested
+// number of machines and every machine of the set runs its latest configuration, or until timeout.
+// A zero requested skips the machine count check.
+//
+// The status alone may still be the ready status from before the last apply, the configuration of
+// the cluster machines tells whether the apply went through.
+func (o *OmniClient) WaitForMachineSetReady(id string, requested uint32, timeout time.Duration) (*omni.MachineSetStatus, error) {
+	ctx, cancel := context.WithTimeout(o.context, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		status, err := o.machineSetReady(ctx, id, requested)
+		if err != nil {
+			return nil, fmt.Errorf("waiting for machine set %s to be ready: %w", id, err)
+		}
+		if status != nil {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for machine set %s to be ready: %w", id, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// machineSetReady returns the status of a machine set when it is ready and its machines are up to date, nil otherwise.
+func (o *OmniClient) machineSetReady(ctx context.Context, id string, requested uint32) (*omni.MachineSetStatus, error) {
+	status, err := safe.StateGetByID[*omni.MachineSetStatus](ctx, o.state, id)
+	if state.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value := status.TypedSpec().Value
+	if !value.Ready || (requested != 0 && value.GetMachines().GetRequested() != requested) {
+		return nil, nil
+	}
+
+	machines, err := safe.StateListAll[*omni.ClusterMachineStatus](ctx, o.state,
+		state.WithLabelQuery(resource.LabelEqual(omni.LabelMachineSet, id)))
+	if err != nil {
+		return nil, err
+	}
+
+	upToDate := true
+	machines.ForEach(func(r *omni.ClusterMachineStatus) {
+		upToDate = upToDate && r.TypedSpec().Value.ConfigUpToDate
+	})
+	if !upToDate {
+		return nil, nil
+	}
+
+	return status, nil
+}
+
+func (o *OmniClient) GetMachineSetStatus(id string) (*omni.MachineSetStatus, error) {
+	return safe.StateGetByID[*omni.MachineSetStatus](o.context, o.state, id)
+}
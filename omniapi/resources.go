@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// ManagedByLabel marks the resources created by the standalone resources of the provider
+// (omni_machine_set, ...). Its value is the Terraform resource type owning them.
+//
+// Cluster template operations do not see these resources, otherwise a sync of omni_cluster
+// would destroy them as they carry the cluster label without being part of the template.
+const ManagedByLabel = "terraform-provider-omni/managed-by"
+
+// errNotFound reports objects missing from the Omni management API, which has no typed error for them.
+var errNotFound = errors.New("not found")
+
+// IsNotFound tells whether err is a missing resource error of the Omni state or of the management API.
+func IsNotFound(err error) bool {
+	return state.IsNotFoundError(err) || errors.Is(err, errNotFound)
+}
+
+// templateState hides the resources carrying ManagedByLabel from cluster template operations.
+type templateState struct {
+	state.State
+}
+
+func (s templateState) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	list, err := s.State.List(ctx, kind, opts...)
+	if err != nil {
+		return list, err
+	}
+
+	items := list.Items[:0]
+	for _, r := range list.Items {
+		if _, managed := r.Metadata().Labels().Get(ManagedByLabel); !managed {
+			items = append(items, r)
+		}
+	}
+	list.Items = items
+
+	return list, nil
+}
+
+// applyResource creates r, or updates it in place when it already exists, labelled as managed by managedBy.
+func (o *OmniClient) applyResource(r resource.Resource, managedBy string) error {
+	ctx := o.context
+	st := o.state
+
+	r.Metadata().Labels().Set(ManagedByLabel, managedBy)
+
+	existing, err := st.Get(ctx, r.Metadata())
+	if state.IsNotFoundError(err) {
+		return st.Create(ctx, r)
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Metadata().Phase() == resource.PhaseTearingDown {
+		return fmt.Errorf("%s %s is being torn down", r.Metadata().Type(), r.Metadata().ID())
+	}
+
+	r.Metadata().SetVersion(existing.Metadata().Version())
+	r.Metadata().Finalizers().Set(*existing.Metadata().Finalizers())
+
+	return st.Update(ctx, r)
+}
+
+// destroyResources tears down every resource, waits for their finalizers to be removed and destroys them.
+// Missing resources are ignored.
+func (o *OmniClient) destroyResources(pointers []resource.Pointer, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(o.context, timeout)
+	defer cancel()
+
+	st := o.state
+
+	var tearingDown []resource.Pointer
+
+	for _, ptr := range pointers {
+		_, err := st.Teardown(ctx, ptr)
+		if state.IsNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		tearingDown = append(tearingDown, ptr)
+	}
+
+	for _, ptr := range tearingDown {
+		_, err := st.WatchFor(ctx, ptr, state.WithCondition(func(r resource.Resource) (bool, error) {
+			return r.Metadata().Finalizers().Empty(), nil
+		}))
+		if err != nil {
+			return fmt.Errorf("waiting for %s %s teardown: %w", ptr.Type(), ptr.ID(), err)
+		}
+
+		if err := st.Destroy(ctx, ptr); err != nil && !state.IsNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
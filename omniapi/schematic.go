@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/safe"
+
+	"github.com/siderolabs/omni/client/api/omni/management"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/omni"
+)
+
+// Schematic is the content of an Image Factory schematic, the SideroLink parameters of Omni are added to it.
+type Schematic struct {
+	Extensions   []string
+	KernelArgs   []string
+	MetaValues   map[uint32]string
+	TalosVersion string
+	SecureBoot   bool
+	// MediaID is the installation media the schematic is generated for, if any.
+	MediaID string
+}
+
+// SchematicImages are the ID of a schematic and the images built out of it.
+type SchematicImages struct {
+	ID             string
+	PXEURL         string
+	InstallerImage string
+}
+
+// CreateSchematic registers a schematic to the Image Factory used by Omni.
+func (o *OmniClient) CreateSchematic(s Schematic) (*SchematicImages, error) {
+	resp, err := o.omniClient.Management().CreateSchematic(o.context, &management.CreateSchematicRequest{
+		Extensions:      s.Extensions,
+		ExtraKernelArgs: s.KernelArgs,
+		MetaValues:      s.MetaValues,
+		TalosVersion:    s.TalosVersion,
+		SecureBoot:      s.SecureBoot,
+		MediaId:         s.MediaID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := o.GetImageFactoryBaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	installer, err := InstallerImage(baseURL, resp.SchematicId, s.TalosVersion, s.SecureBoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SchematicImages{
+		ID:             resp.SchematicId,
+		PXEURL:         resp.PxeUrl,
+		InstallerImage: installer,
+	}, nil
+}
+
+// GetImageFactoryBaseURL returns the URL of the Image Factory used by Omni.
+func (o *OmniClient) GetImageFactoryBaseURL() (string, error) {
+	features, err := safe.StateGetByID[*omni.FeaturesConfig](o.context, o.state, omni.FeaturesConfigID)
+	if err != nil {
+		return "", err
+	}
+
+	return features.TypedSpec().Value.ImageFactoryBaseUrl, nil
+}
+
+// InstallerImage returns the reference of the Talos installer image of a schematic served by an Image Factory.
+func InstallerImage(factoryBaseURL, schematicID, talosVersion string, secureBoot bool) (string, error) {
+	u, err := url.Parse(factoryBaseURL)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid image factory URL %q", factoryBaseURL)
+	}
+
+	installer := "installer"
+	if secureBoot {
+		installer = "installer-secureboot"
+	}
+
+	return fmt.Sprintf("%s%s/%s/%s:%s", u.Host, strings.TrimSuffix(u.Path, "/"), installer, schematicID, talosVersion), nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package omniapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/google/uuid"
+
+	"github.com/siderolabs/omni/client/pkg/omni/resources"
+	"github.com/siderolabs/omni/client/pkg/omni/resources/auth"
+)
+
+// User is an Omni user: an Identity named after the email, pointing to the User holding the role.
+// SAMLLabels are the identity labels under the SAML prefix, keys without the prefix.
+type User struct {
+	Email      string
+	UserID     string
+	Role       string
+	SAMLLabels map[string]string
+}
+
+// CreateUser creates the Identity and User of an email, the way omnictl does.
+func (o *OmniClient) CreateUser(email, role string, samlLabels map[string]string) (*User, error) {
+	ctx := o.context
+	st := o.state
+
+	_, err := safe.StateGetByID[*auth.Identity](ctx, st, email)
+	if err == nil {
+		return nil, fmt.Errorf("identity with email %q already exists", email)
+	}
+	if !state.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	user := auth.NewUser(resources.DefaultNamespace, uuid.NewString())
+	user.TypedSpec().Value.Role = role
+
+	identity := auth.NewIdentity(resources.DefaultNamespace, email)
+	identity.Metadata().Labels().Set(auth.LabelIdentityUserID, user.Metadata().ID())
+	identity.TypedSpec().Value.UserId = user.Metadata().ID()
+
+	for key, value := range samlLabels {
+		identity.Metadata().Labels().Set(auth.SAMLLabelPrefix+key, value)
+	}
+
+	if err := st.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := st.Create(ctx, identity); err != nil {
+		// Without its identity, the user can't be reached anymore.
+		if destroyErr := st.Destroy(ctx, user.Metadata()); destroyErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("cleaning up user %s: %w", user.Metadata().ID(), destroyErr))
+		}
+
+		return nil, err
+	}
+
+	return &User{
+		Email:      email,
+		UserID:     user.Metadata().ID(),
+		Role:       role,
+		SAMLLabels: samlLabels,
+	}, nil
+}
+
+// GetUser returns the user of an email. Service account identities are rejected.
+func (o *OmniClient) GetUser(email string) (*User, error) {
+	identity, err := safe.StateGetByID[*auth.Identity](o.context, o.state, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := identity.Metadata().Labels().Get(auth.LabelIdentityTypeServiceAccount); ok {
+		return nil, fmt.Errorf("identity %q is a service account", email)
+	}
+
+	user, err := safe.StateGetByID[*auth.User](o.context, o.state, identity.TypedSpec().Value.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &User{
+		Email:      email,
+		UserID:     user.Metadata().ID(),
+		Role:       user.TypedSpec().Value.Role,
+		SAMLLabels: map[string]string{},
+	}
+
+	for key, value := range identity.Metadata().Labels().Raw() {
+		if name, ok := strings.CutPrefix(key, auth.SAMLLabelPrefix); ok {
+			result.SAMLLabels[name] = value
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateUser sets the role of the user of an email and replaces its SAML labels.
+func (o *OmniClient) UpdateUser(email, role string, samlLabels map[string]string) error {
+	ctx := o.context
+	st := o.state
+
+	identity, err := safe.StateUpdateWithConflicts(ctx, st, auth.NewIdentity(resources.DefaultNamespace, email).Metadata(), func(r *auth.Identity) error {
+		for _, key := range r.Metadata().Labels().Keys() {
+			if strings.HasPrefix(key, auth.SAMLLabelPrefix) {
+				r.Metadata().Labels().Delete(key)
+			}
+		}
+
+		for key, value := range samlLabels {
+			r.Metadata().Labels().Set(auth.SAMLLabelPrefix+key, value)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = safe.StateUpdateWithConflicts(ctx, st, auth.NewUser(resources.DefaultNamespace, identity.TypedSpec().Value.UserId).Metadata(), func(r *auth.User) error {
+		r.TypedSpec().Value.Role = role
+
+		return nil
+	})
+
+	return err
+}
+
+// DeleteUser destroys the Identity of an email and its User.
+func (o *OmniClient) DeleteUser(email string) error {
+	identity, err := safe.StateGetByID[*auth.Identity](o.context, o.state, email)
+	if state.IsNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return o.destroyResources([]resource.Pointer{
+		identity.Metadata(),
+		auth.NewUser(resources.DefaultNamespace, identity.TypedSpec().Value.UserId).Metadata(),
+	}, 5*time.Minute)
+}